@@ -0,0 +1,66 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/clause"
+)
+
+// RawCondition builds the SQL condition of a param of Config.RawConditions
+// for value, which is bound as one of vars, eg : "total > ?", and never
+// written into the SQL. It returns false to reject the value.
+type RawCondition func(c *gin.Context, value string) (sql string, vars []interface{}, ok bool)
+
+// aliasFilter returns the filter of the param key if it is an alias of
+// config.ExpressionAliases, along with an error if value has no condition.
+func (config Config) aliasFilter(key, value string) (Filter, bool, error) {
+	conditions, ok := config.ExpressionAliases[key]
+	if !ok {
+		return Filter{}, false, nil
+	}
+	sql, ok := conditions[value]
+	if !ok {
+		return Filter{}, true, errors.New("unknown value")
+	}
+	// gorm wraps the conditions joined with AND or OR in parentheses.
+	return Filter{Param: key, Operator: "eq", Value: value, expr: clause.Expr{SQL: sql}}, true, nil
+}
+
+// rawConditionFilter returns the filter of the param key if it is registered
+// in config.RawConditions, along with an error if the condition rejects value.
+func (config Config) rawConditionFilter(c *gin.Context, key, value string) (Filter, bool, error) {
+	condition, ok := config.RawConditions[key]
+	if !ok {
+		return Filter{}, false, nil
+	}
+	sql, vars, ok := condition(c, value)
+	if !ok {
+		return Filter{}, true, errors.New("invalid value")
+	}
+	return Filter{Param: key, Operator: "eq", Value: value, expr: clause.Expr{SQL: sql, Vars: vars}}, true, nil
+}
+
+// existsFilter returns the filter of the param key if it is registered in
+// config.ExistsFilters, along with an error if value is not a boolean.
+func (config Config) existsFilter(key, value string) (Filter, bool, error) {
+	subquery, ok := config.ExistsFilters[key]
+	if !ok {
+		return Filter{}, false, nil
+	}
+	exists, err := strconv.ParseBool(value)
+	if err != nil {
+		return Filter{}, true, errors.New("invalid boolean")
+	}
+	sql := "EXISTS (" + subquery + ")"
+	if !exists {
+		sql = "NOT " + sql
+	}
+	return Filter{Param: key, Operator: "eq", Value: value, expr: clause.Expr{SQL: sql}}, true, nil
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+)
+
+// TestFiltersExpressionAlias checks that an alias applies its static condition.
+func (s *TestSuite) TestFiltersExpressionAlias() {
+	var users []User
+	ctx := newTestContext("active=true&username=sampleUser")
+	config := Config{Flags: FILTER, ExpressionAliases: map[string]map[string]string{
+		"active": {
+			"true":  "deleted_at IS NULL AND banned = false",
+			"false": "deleted_at IS NOT NULL OR banned = true",
+		},
+	}}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE \(deleted_at IS NULL AND banned = false\) AND "username" = \$1$`).
+		WithArgs("sampleUser").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err := s.db.Model(&User{}).Scopes(FilterByConfig(ctx, config)).Find(&users).Error
+	s.NoError(err)
+
+	config.Strict = true
+	_, err = ParseQuery(newTestContext("active=maybe"), &User{}, config)
+	s.EqualError(err, "filter: active: unknown value")
+}
+
+// TestFiltersRawCondition checks that a registered condition is applied with
+// its bound vars, and that the rejected values are reported in strict mode.
+func (s *TestSuite) TestFiltersRawCondition() {
+	var users []User
+	ctx := newTestContext("domain=example.com&username=sampleUser")
+	config := Config{Flags: FILTER, RawConditions: map[string]RawCondition{
+		"domain": func(_ *gin.Context, value string) (string, []interface{}, bool) {
+			if value == "" {
+				return "", nil, false
+			}
+			return "split_part(email, '@', 2) = ? OR email LIKE ?", []interface{}{value, "%." + value}, true
+		},
+	}}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE \(split_part\(email, '@', 2\) = \$1 OR email LIKE \$2\) AND "username" = \$3$`).
+		WithArgs("example.com", "%.example.com", "sampleUser").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err := s.db.Model(&User{}).Scopes(FilterByConfig(ctx, config)).Find(&users).Error
+	s.NoError(err)
+
+	config.Strict = true
+	_, err = ParseQuery(newTestContext("domain="), &User{}, config)
+	s.EqualError(err, "filter: domain: invalid value")
+}
+
+// TestFiltersExists checks that a registered subquery is matched with EXISTS
+// for true and NOT EXISTS for false.
+func (s *TestSuite) TestFiltersExists() {
+	var users []User
+	subquery := "SELECT 1 FROM payments WHERE payments.user_id = users.id HAVING sum(amount) > 1000"
+	config := Config{Flags: FILTER, ExistsFilters: map[string]string{"is_vip": subquery}}
+	for value, condition := range map[string]string{
+		"true":  "EXISTS (" + subquery + ")",
+		"false": "NOT EXISTS (" + subquery + ")",
+	} {
+		s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE ` + regexp.QuoteMeta(condition) + `$`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+		err := s.db.Model(&User{}).Scopes(FilterByConfig(newTestContext("is_vip="+value), config)).Find(&users).Error
+		s.NoError(err, value)
+	}
+
+	config.Strict = true
+	_, err := ParseQuery(newTestContext("is_vip=maybe"), &User{}, config)
+	s.EqualError(err, "filter: is_vip: invalid boolean")
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"errors"
+	"strconv"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func init() {
+	operators["between"] = func(db *gorm.DB, config Config, f Filter) clause.Expression {
+		if len(f.Values) != 2 {
+			return nil
+		}
+		return clause.Expr{
+			SQL:  "? BETWEEN ? AND ?",
+			Vars: []interface{}{f.column(db), f.arg(config, f.Values[0]), f.arg(config, f.Values[1])},
+		}
+	}
+	listOperators["between"] = true
+}
+
+// arity is the number of values expected by a list operator, max being 0 if
+// the operator takes any number of values.
+type arity struct {
+	min, max int
+}
+
+// operatorArities are the arities of the list operators, eg : "between"
+// takes the 2 bounds of the range.
+var operatorArities = map[string]arity{
+	"in":      {min: 1},
+	"between": {min: 2, max: 2},
+}
+
+// checkArity checks that operator can take count values.
+func checkArity(operator string, count int) error {
+	a, ok := operatorArities[operator]
+	if !ok {
+		return nil
+	}
+	switch {
+	case a.min == a.max && count != a.min:
+		return errors.New("expects " + pluralValues(a.min))
+	case count < a.min:
+		return errors.New("expects at least " + pluralValues(a.min))
+	case a.max > 0 && count > a.max:
+		return errors.New("expects at most " + pluralValues(a.max))
+	}
+	return nil
+}
+
+func pluralValues(count int) string {
+	if count == 1 {
+		return "1 value"
+	}
+	return strconv.Itoa(count) + " values"
+}
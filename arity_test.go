@@ -0,0 +1,79 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// TestFiltersBetween checks that the bounds of a between filter are bound.
+func (s *TestSuite) TestFiltersBetween() {
+	var products []Product
+	ctx := newTestContext("price__between=10,20")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "products" WHERE "price" BETWEEN \$1 AND \$2$`).
+		WithArgs("10", "20").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "price"}))
+	err := s.db.Model(&Product{}).Scopes(FilterByConfig(ctx, Config{Flags: FILTER})).Find(&products).Error
+	s.NoError(err)
+}
+
+// TestFiltersOperatorArity checks that the filters with too few or too many
+// values are reported in strict mode and skipped otherwise.
+func (s *TestSuite) TestFiltersOperatorArity() {
+	config := Config{Flags: FILTER, Strict: true}
+	for query, reason := range map[string]string{
+		"price__between=10":        "filter: price__between: expects 2 values",
+		"price__between=10,20,30":  "filter: price__between: expects 2 values",
+		"price__between=10,twenty": "filter: price__between: invalid number",
+		"name__in=,":               "filter: name__in: expects at least 1 value",
+	} {
+		_, err := ParseQuery(newTestContext(query), &Product{}, config)
+		s.EqualError(err, reason, query)
+	}
+
+	var products []Product
+	s.mock.ExpectQuery(`^SELECT \* FROM "products" WHERE "name" = \$1$`).
+		WithArgs("pen").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "price"}))
+	err := s.db.Model(&Product{}).Scopes(FilterByConfig(newTestContext("price__between=10&name=pen"), Config{Flags: FILTER})).Find(&products).Error
+	s.NoError(err)
+}
+
+// TestFiltersBetweenRewrite checks that a filter rewritten to a between
+// with a single bound is reported in strict mode and skipped otherwise.
+func (s *TestSuite) TestFiltersBetweenRewrite() {
+	config := Config{Flags: FILTER, RewriteFilter: func(param, operator, value string) (string, string, bool) {
+		if param == "price" {
+			return "between", value, true
+		}
+		return operator, value, true
+	}}
+
+	var products []Product
+	s.mock.ExpectQuery(`^SELECT \* FROM "products" WHERE "name" = \$1$`).
+		WithArgs("pen").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "price"}))
+	err := s.db.Model(&Product{}).Scopes(FilterByConfig(newTestContext("price=10&name=pen"), config)).Find(&products).Error
+	s.NoError(err)
+
+	config.Strict = true
+	_, err = ParseQuery(newTestContext("price=10"), &Product{}, config)
+	s.EqualError(err, "filter: price: expects 2 values")
+}
+
+// TestBetweenWithoutValues checks that a between filter without its 2 bounds
+// builds no condition, eg : in a where expression.
+func (s *TestSuite) TestBetweenWithoutValues() {
+	for _, values := range [][]string{nil, {"10"}} {
+		f := Filter{Column: "price", Operator: "between", Values: values}
+		s.Nil(f.expression(s.db, Config{}), values)
+	}
+
+	q, err := ParseQuery(newTestContext("where=name__between=a,b"), &Product{}, Config{Flags: FILTER})
+	s.NoError(err)
+	s.NotPanics(func() { q.Where.expression(s.db, q.Config) })
+}
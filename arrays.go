@@ -0,0 +1,125 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// The semantics of the equality filters of the array fields on Postgres, eg :
+// "tags=go,rust" matches the tags containing "go" and "rust" with
+// arrayContains, or any of them with arrayOverlaps.
+const (
+	arrayContains = "contains"
+	arrayOverlaps = "overlaps"
+)
+
+var arrayKeyRegexp = regexp.MustCompile(`^(\w+)\[(\d*)\]$`)
+
+// compactArrays turns the bracket array params, eg : "id[0]=1&id[2]=3" or
+// "id[]=1&id[]=3", into "id__in" params. The elements are sorted by index and
+// the gaps between indexes are dropped, the elements without index come last.
+func compactArrays(values url.Values) url.Values {
+	type element struct {
+		index int
+		value string
+	}
+	var (
+		compacted = make(url.Values, len(values))
+		arrays    = map[string][]element{}
+	)
+	for key, keyValues := range values {
+		match := arrayKeyRegexp.FindStringSubmatch(key)
+		if match == nil {
+			compacted[key] = keyValues
+			continue
+		}
+		index := math.MaxInt
+		if match[2] != "" {
+			var err error
+			if index, err = strconv.Atoi(match[2]); err != nil {
+				continue
+			}
+		}
+		for _, value := range keyValues {
+			arrays[match[1]] = append(arrays[match[1]], element{index: index, value: value})
+		}
+	}
+	for name, elements := range arrays {
+		sort.SliceStable(elements, func(i, j int) bool {
+			return elements[i].index < elements[j].index
+		})
+		key := name + suffixSeparator + "in"
+		for _, element := range elements {
+			compacted[key] = append(compacted[key], element.value)
+		}
+	}
+	return compacted
+}
+
+// splitList splits the comma separated values of a list param.
+func splitList(values []string) []string {
+	var list []string
+	for _, value := range values {
+		for _, element := range strings.Split(value, ",") {
+			if element = strings.TrimSpace(element); element != "" {
+				list = append(list, element)
+			}
+		}
+	}
+	return list
+}
+
+// repeatedValues returns the values of the repeated equality param key, eg :
+// "active" and "pending" for "status=active&status=pending".
+func repeatedValues(key string, values []string) []string {
+	list := make([]string, 0, len(values))
+	for _, value := range values {
+		if _, value, separator := getSeparator(key, value); separator == eq {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+var postgresArrayEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// postgresArray returns the Postgres array literal of values, eg : {"1","2"}.
+// Its type is inferred from the compared column.
+func postgresArray(values []interface{}) string {
+	elements := make([]string, 0, len(values))
+	for _, value := range values {
+		elements = append(elements, `"`+postgresArrayEscaper.Replace(fmt.Sprint(value))+`"`)
+	}
+	return "{" + strings.Join(elements, ",") + "}"
+}
+
+// arrayExpression compares the array column of f to its comma separated or
+// repeated values, eg : "tags" @> '{"go","rust"}' or "tags" && '{"go","rust"}'.
+func arrayExpression(db *gorm.DB, f Filter) clause.Expression {
+	list := f.Values
+	if f.Operator != "in" {
+		list = splitList([]string{f.Value})
+	}
+	values := make([]interface{}, 0, len(list))
+	for _, value := range list {
+		values = append(values, value)
+	}
+	symbol := "@>"
+	if f.arrayMode == arrayOverlaps {
+		symbol = "&&"
+	}
+	return clause.Expr{SQL: "? " + symbol + " ?", Vars: []interface{}{f.column(db), postgresArray(values)}}
+}
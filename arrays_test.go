@@ -0,0 +1,126 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type Ticket struct {
+	Id    int64  `filter:"filterable"`
+	State string `filter:"filterable"`
+}
+
+// TestFiltersArrayGaps checks that indexed arrays with gaps are compacted.
+func (s *TestSuite) TestFiltersArrayGaps() {
+	var tickets []Ticket
+	ctx := newTestContext("id[2]=3&id[0]=1")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "tickets" WHERE "id" IN \(\$1,\$2\)$`).
+		WithArgs("1", "3").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "state"}))
+	err := s.db.Model(&Ticket{}).Scopes(FilterByQuery(ctx, FILTER)).Find(&tickets).Error
+	s.NoError(err)
+}
+
+// TestFiltersIn checks the in operator with comma separated and repeated values.
+func (s *TestSuite) TestFiltersIn() {
+	var tickets []Ticket
+	ctx := newTestContext("state__in=open,closed&state__in=pending")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "tickets" WHERE "state" IN \(\$1,\$2,\$3\)$`).
+		WithArgs("open", "closed", "pending").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "state"}))
+	err := s.db.Model(&Ticket{}).Scopes(FilterByQuery(ctx, FILTER)).Find(&tickets).Error
+	s.NoError(err)
+}
+
+// TestFiltersEmptyIn checks that a list left empty matches no rows by default.
+func (s *TestSuite) TestFiltersEmptyIn() {
+	var documents []Document
+	ctx := newTestContext("owner_id__in=@me")
+	config := Config{Flags: FILTER, CurrentUserKey: "user_id"}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "documents" WHERE 1=0$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "owner_id"}))
+	err := s.db.Model(&Document{}).Scopes(FilterByConfig(ctx, config)).Find(&documents).Error
+	s.NoError(err)
+
+	config.IgnoreEmptyLists = true
+	s.mock.ExpectQuery(`^SELECT \* FROM "documents"$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "owner_id"}))
+	err = s.db.Model(&Document{}).Scopes(FilterByConfig(ctx, config)).Find(&documents).Error
+	s.NoError(err)
+}
+
+// TestFiltersInDropsInvalidValues checks that the valid values of a list are kept.
+func (s *TestSuite) TestFiltersInDropsInvalidValues() {
+	var documents []Document
+	ctx := newTestContext("owner_id__in=@me,7")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "documents" WHERE "owner_id" = \$1$`).
+		WithArgs("7").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "owner_id"}))
+	err := s.db.Model(&Document{}).Scopes(FilterByConfig(ctx, Config{Flags: FILTER, CurrentUserKey: "user_id"})).Find(&documents).Error
+	s.NoError(err)
+}
+
+// TestFiltersInArrayBinding checks that the values are bound as a single array.
+func (s *TestSuite) TestFiltersInArrayBinding() {
+	var tickets []Ticket
+	ctx := newTestContext(`state__in=open,"closed",wont\fix`)
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "tickets" WHERE "state" = ANY\(\$1\)$`).
+		WithArgs(`{"open","\"closed\"","wont\\fix"}`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "state"}))
+	err := s.db.Model(&Ticket{}).Scopes(FilterByConfig(ctx, Config{Flags: FILTER, ArrayBinding: true})).Find(&tickets).Error
+	s.NoError(err)
+}
+
+// TestFiltersRepeatedParams checks that the repeated equality params match any
+// of their values.
+func (s *TestSuite) TestFiltersRepeatedParams() {
+	var tickets []Ticket
+	ctx := newTestContext("state=active&state=pending,late")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "tickets" WHERE "state" IN \(\$1,\$2\)$`).
+		WithArgs("active", "pending,late").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "state"}))
+	err := s.db.Model(&Ticket{}).Scopes(FilterByQuery(ctx, FILTER)).Find(&tickets).Error
+	s.NoError(err)
+}
+
+type Repository struct {
+	Id        int64
+	Tags      []string `gorm:"type:text[]" filter:"filterable"`
+	Languages []string `gorm:"type:text[]" filter:"filterable;array:overlaps"`
+}
+
+// TestFiltersArrayContains checks that the array fields contain every value
+// by default.
+func (s *TestSuite) TestFiltersArrayContains() {
+	var repositories []Repository
+	for _, query := range []string{"tags=go,rust", "tags=go&tags=rust", "tags__in=go,rust"} {
+		s.mock.ExpectQuery(`^SELECT \* FROM "repositories" WHERE "tags" @> \$1$`).
+			WithArgs(`{"go","rust"}`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "tags", "languages"}))
+		err := s.db.Model(&Repository{}).Scopes(FilterByQuery(newTestContext(query), FILTER)).Find(&repositories).Error
+		s.NoError(err, query)
+	}
+}
+
+// TestFiltersArrayOverlaps checks that the array fields tagged overlaps
+// contain any of the values.
+func (s *TestSuite) TestFiltersArrayOverlaps() {
+	var repositories []Repository
+	ctx := newTestContext("languages=go,rust")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "repositories" WHERE "languages" && \$1$`).
+		WithArgs(`{"go","rust"}`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "tags", "languages"}))
+	err := s.db.Model(&Repository{}).Scopes(FilterByQuery(ctx, FILTER)).Find(&repositories).Error
+	s.NoError(err)
+}
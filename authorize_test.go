@@ -0,0 +1,69 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"net/url"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+)
+
+type Employee struct {
+	Id     int64
+	Name   string `filter:"filterable"`
+	Salary int64  `filter:"filterable"`
+}
+
+// managersOnly lets only the managers filter and order by salary.
+func managersOnly(c *gin.Context, field string, _ int) bool {
+	return field != "salary" || c.GetString("role") == "manager"
+}
+
+// TestAuthorizeFieldAllowed checks that the allowed fields are applied.
+func (s *TestSuite) TestAuthorizeFieldAllowed() {
+	var employees []Employee
+	ctx := newTestContext("salary__gte=1000&order_by=salary")
+	ctx.Set("role", "manager")
+	config := Config{Flags: FILTER | ORDER_BY, AuthorizeField: managersOnly}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "employees" WHERE "salary" >= \$1 ORDER BY "employees"."salary" DESC$`).
+		WithArgs("1000").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "salary"}))
+	err := s.db.Model(&Employee{}).Scopes(FilterByConfig(ctx, config)).Find(&employees).Error
+	s.NoError(err)
+}
+
+// TestAuthorizeFieldDenied checks that the denied fields are ignored, or
+// reported in strict mode.
+func (s *TestSuite) TestAuthorizeFieldDenied() {
+	var employees []Employee
+	ctx := newTestContext("salary__gte=1000&name=bob&order_by=-salary,name")
+	config := Config{Flags: FILTER | ORDER_BY, AuthorizeField: managersOnly}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "employees" WHERE "name" = \$1 ORDER BY "employees"."name" DESC$`).
+		WithArgs("bob").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "salary"}))
+	err := s.db.Model(&Employee{}).Scopes(FilterByConfig(ctx, config)).Find(&employees).Error
+	s.NoError(err)
+
+	config.Strict = true
+	_, err = ParseQuery(ctx, &Employee{}, config)
+	s.EqualError(err, "filter: salary__gte: unauthorized filter")
+
+	_, err = ParseQuery(newTestContext("order_by=salary"), &Employee{}, config)
+	s.EqualError(err, "filter: order_by: unauthorized column salary")
+}
+
+// TestAuthorizeFieldWhere checks that the where expression rejects the denied
+// fields.
+func (s *TestSuite) TestAuthorizeFieldWhere() {
+	ctx := newTestContext("where=" + url.QueryEscape("name=bob OR salary>1000"))
+	config := Config{Flags: FILTER, AuthorizeField: managersOnly, Strict: true}
+
+	_, err := ParseQuery(ctx, &Employee{}, config)
+	s.EqualError(err, "filter: where: unauthorized filter salary in where expression")
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"errors"
+	"strconv"
+
+	"gorm.io/gorm/clause"
+)
+
+// BooleanCombination is a condition on two boolean columns. Operator is
+// "xor", exactly one of the columns being true, "and" or "or". The condition
+// is negated when the param is false.
+type BooleanCombination struct {
+	Left     string
+	Right    string
+	Operator string
+}
+
+// booleanCombinationSQL are the conditions of the operators of the boolean
+// combinations, when the param is true and when it is false.
+var booleanCombinationSQL = map[string][2]string{
+	"xor": {"(? <> ?)", "(? = ?)"},
+	"and": {"(? AND ?)", "NOT (? AND ?)"},
+	"or":  {"(? OR ?)", "NOT (? OR ?)"},
+}
+
+// booleanCombinationFilter returns the filter of the param key if it is
+// registered in config.BooleanCombinations, along with an error if value is
+// not a boolean.
+func (config Config) booleanCombinationFilter(key, value string) (Filter, bool, error) {
+	combination, ok := config.BooleanCombinations[key]
+	if !ok {
+		return Filter{}, false, nil
+	}
+	conditions, ok := booleanCombinationSQL[combination.Operator]
+	if !ok {
+		return Filter{}, true, errors.New("unknown combination operator " + combination.Operator)
+	}
+	matched, err := strconv.ParseBool(value)
+	if err != nil {
+		return Filter{}, true, errors.New("invalid boolean")
+	}
+	sql := conditions[0]
+	if !matched {
+		sql = conditions[1]
+	}
+	return Filter{Param: key, Operator: "eq", Value: value, expr: clause.Expr{
+		SQL:  sql,
+		Vars: []interface{}{clause.Column{Name: combination.Left}, clause.Column{Name: combination.Right}},
+	}}, true, nil
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type Edge struct {
+	Id       int64
+	Inbound  bool
+	Outbound bool
+}
+
+// TestFiltersBooleanCombination checks the conditions of the boolean
+// combinations, negated when the param is false.
+func (s *TestSuite) TestFiltersBooleanCombination() {
+	var edges []Edge
+	config := Config{Flags: FILTER, BooleanCombinations: map[string]BooleanCombination{
+		"only_one_flag": {Left: "inbound", Right: "outbound", Operator: "xor"},
+		"both":          {Left: "inbound", Right: "outbound", Operator: "and"},
+	}}
+	for query, condition := range map[string]string{
+		"only_one_flag=true":  `("inbound" <> "outbound")`,
+		"only_one_flag=false": `("inbound" = "outbound")`,
+		"both=true":           `("inbound" AND "outbound")`,
+		"both=false":          `NOT ("inbound" AND "outbound")`,
+	} {
+		s.mock.ExpectQuery(`^SELECT \* FROM "edges" WHERE ` + regexp.QuoteMeta(condition) + `$`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "inbound", "outbound"}))
+		err := s.db.Model(&Edge{}).Scopes(FilterByConfig(newTestContext(query), config)).Find(&edges).Error
+		s.NoError(err, query)
+	}
+
+	config.Strict = true
+	_, err := ParseQuery(newTestContext("only_one_flag=maybe"), &Edge{}, config)
+	s.EqualError(err, "filter: only_one_flag: invalid boolean")
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Key returns a canonical representation of the parsed query: filters are
+// sorted and only the enabled capabilities are included, so two equivalent
+// requests always produce the same key whatever the order of their params.
+func (q *ParsedQuery) Key() string {
+	var parts []string
+	if q.Config.Flags&FILTER > 0 {
+		for _, f := range q.Filters {
+			operator := f.Operator
+			if f.OrNull {
+				operator += orNullSuffix
+			}
+			column := strings.Join(append([]string{f.Column}, f.jsonPath...), ".")
+			if f.expr != nil || f.having != nil {
+				column = "param:" + f.Param
+			}
+			parts = append(parts, "filter:"+url.QueryEscape(column)+" "+operator+" "+url.QueryEscape(f.value()))
+		}
+		sort.Strings(parts)
+		if q.Where != nil {
+			parts = append(parts, "where:"+url.QueryEscape(q.Where.String()))
+		}
+	}
+	if len(q.SearchColumns) > 0 {
+		parts = append(parts, "search:"+url.QueryEscape(q.Params.Search)+" in "+strings.Join(q.SearchColumns, ","))
+	}
+	if q.Config.Flags&PAGINATE > 0 {
+		parts = append(parts,
+			"page:"+strconv.Itoa(q.Params.Page),
+			"limit:"+strconv.Itoa(q.Params.Limit),
+		)
+		if q.Params.Offset > 0 {
+			parts = append(parts, "offset:"+strconv.Itoa(q.Params.Offset))
+		}
+	}
+	if q.Config.Flags&ORDER_BY > 0 {
+		order := "order:" + url.QueryEscape(q.Params.OrderBy) + " " + q.Params.OrderDirection
+		if q.Params.OrderNulls != "" {
+			order += " nulls " + q.Params.OrderNulls
+		}
+		parts = append(parts, order)
+	}
+	if q.Params.DistinctOn != "" {
+		parts = append(parts, "distinct_on:"+url.QueryEscape(q.Params.DistinctOn))
+	}
+	if q.Params.Distinct {
+		parts = append(parts, "distinct:true")
+	}
+	if q.Params.WithCount != "" && len(q.Config.WithCount) > 0 {
+		parts = append(parts, "with_count:"+url.QueryEscape(q.Params.WithCount))
+	}
+	return strings.Join(parts, "&")
+}
+
+// CanonicalKey returns a stable key for the request, suitable for an ETag or
+// a cache key. An empty string is returned if the query params can't be bound.
+func CanonicalKey(c *gin.Context, model interface{}, config Config) string {
+	query, err := ParseQuery(c, model, config)
+	if err != nil {
+		return ""
+	}
+	return query.Key()
+}
+
+// ToQueryString returns the query string of the parsed query, eg : for a link
+// editing the filters of the request. Like Key, it is stable whatever the
+// order of the params of the request, and parsing it gives an equivalent
+// query.
+func ToQueryString(q *ParsedQuery) string {
+	values := url.Values{}
+	if q.Config.Flags&FILTER > 0 {
+		for _, f := range q.Filters {
+			parts := []Filter{f}
+			if len(f.parts) > 0 {
+				// The composite keys are sent param by param.
+				parts = f.parts
+			}
+			for _, part := range parts {
+				key := part.Param
+				if part.Operator != "eq" || part.OrNull {
+					operator := part.Operator
+					if part.OrNull {
+						operator += orNullSuffix
+					}
+					key += suffixSeparator + operator
+				}
+				values.Add(key, part.value())
+			}
+		}
+		if q.where != "" {
+			values.Set(whereParam, q.where)
+		}
+	}
+	if len(q.SearchColumns) > 0 {
+		values.Set("search", q.Params.Search)
+	}
+	if q.Config.Flags&PAGINATE > 0 {
+		values.Set("page", strconv.Itoa(q.Params.Page))
+		values.Set("limit", strconv.Itoa(q.Params.Limit))
+		if q.Params.Offset > 0 {
+			values.Set("offset", strconv.Itoa(q.Params.Offset))
+		}
+	}
+	if q.Config.Flags&ORDER_BY > 0 {
+		values.Set("order_by", q.Params.OrderBy)
+		values.Set("order_direction", q.Params.OrderDirection)
+		if q.Params.OrderNulls != "" {
+			values.Set("order_nulls", q.Params.OrderNulls)
+		}
+	}
+	if q.Params.DistinctOn != "" {
+		values.Set("distinct_on", q.Params.DistinctOn)
+	}
+	if q.Params.Distinct {
+		values.Set("distinct", "true")
+	}
+	if q.Params.WithCount != "" && len(q.Config.WithCount) > 0 {
+		values.Set("with_count", q.Params.WithCount)
+	}
+	return values.Encode()
+}
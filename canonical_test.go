@@ -0,0 +1,58 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+// TestCanonicalKeyParamOrder checks that equivalent requests share the same key.
+func (s *TestSuite) TestCanonicalKeyParamOrder() {
+	config := Config{Flags: ALL}
+	first := CanonicalKey(newTestContext("username=sampleUser&email=a@b.c&page=2&limit=10"), &User{}, config)
+	second := CanonicalKey(newTestContext("limit=10&email=a@b.c&page=2&username=sampleUser"), &User{}, config)
+
+	s.NotEmpty(first)
+	s.Equal(first, second)
+	s.NotEqual(first, CanonicalKey(newTestContext("username=otherUser&email=a@b.c&page=2&limit=10"), &User{}, config))
+}
+
+// TestCanonicalKeyNormalized checks that the key is built from normalized values.
+func (s *TestSuite) TestCanonicalKeyNormalized() {
+	config := Config{Flags: PAGINATE | ORDER_BY}
+	s.Equal(
+		"page:1&limit:100&order:created_at desc",
+		CanonicalKey(newTestContext("limit=500&username=sampleUser"), &User{}, config),
+	)
+}
+
+// TestToQueryStringRoundTrip checks that the query string of a parsed request
+// is stable and parses to the same query.
+func (s *TestSuite) TestToQueryStringRoundTrip() {
+	config := Config{Flags: ALL}
+	query, err := ParseQuery(newTestContext("username=john%20doe&search=jo&username__neq_or_null=admin&email__in=a@b.c,d@e.f&sort=-username&page=2"), &User{}, config)
+	s.Require().NoError(err)
+
+	queryString := ToQueryString(query)
+	s.Equal("email__in=a%40b.c%2Cd%40e.f&limit=20&order_by=username&order_direction=desc&page=2&search=jo&username=john+doe&username__neq_or_null=admin", queryString)
+
+	parsed, err := ParseQuery(newTestContext(queryString), &User{}, config)
+	s.Require().NoError(err)
+	s.Equal(query.Key(), parsed.Key())
+	s.Equal(queryString, ToQueryString(parsed))
+}
+
+// TestToQueryStringCompositeRoundTrip checks that a composite key is written
+// param by param and parsed back into the same composite filter.
+func (s *TestSuite) TestToQueryStringCompositeRoundTrip() {
+	config := Config{Flags: FILTER, CompositeFilters: [][]string{{"region", "code"}}}
+	query, err := ParseQuery(newTestContext("region=EU&code=123&name=Main"), &Warehouse{}, config)
+	s.Require().NoError(err)
+
+	queryString := ToQueryString(query)
+	s.Equal("code=123&name=Main&region=EU", queryString)
+
+	parsed, err := ParseQuery(newTestContext(queryString), &Warehouse{}, config)
+	s.Require().NoError(err)
+	s.Equal(query.Key(), parsed.Key())
+	s.Equal(publicFilters(query.Filters), publicFilters(parsed.Filters))
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Capabilities describes what a client may send for a model.
+type Capabilities struct {
+	Filterable []string `json:"filterable"`
+	Searchable []string `json:"searchable"`
+	Orderable  []string `json:"orderable"`
+	Operators  []string `json:"operators"`
+}
+
+// ModelCapabilities introspects the `filter` tags of model. Only the
+// capabilities enabled in config are reported, without the params of
+// config.DisabledParams and the operators it doesn't allow. The orderable
+// columns are the ones of the fields with a `filter` tag. config.AuthorizeField
+// is called with a nil context, WriteCapabilities passes the request.
+func ModelCapabilities(model interface{}, config Config) Capabilities {
+	return modelCapabilities(nil, model, config)
+}
+
+func modelCapabilities(c *gin.Context, model interface{}, config Config) Capabilities {
+	capabilities := Capabilities{
+		Filterable: []string{},
+		Searchable: []string{},
+		Orderable:  []string{},
+		Operators:  []string{},
+	}
+	modelType := reflect.TypeOf(model)
+	if model == nil || modelType.Kind() != reflect.Ptr || modelType.Elem().Kind() != reflect.Struct {
+		return capabilities
+	}
+	modelType = modelType.Elem()
+
+	filterable := func(param string) {
+		if !config.disabled(param) && config.authorized(c, param, FILTER) {
+			capabilities.Filterable = append(capabilities.Filterable, param)
+		}
+	}
+	for i := 0; i < modelType.NumField(); i++ {
+		field := modelType.Field(i)
+		if config.Flags&FILTER > 0 {
+			if param, _, ok := fieldParam(field); ok {
+				filterable(param)
+			}
+			if hasTagFlag(field, "json") {
+				for _, sub := range jsonFields(field) {
+					filterable(sub.param)
+				}
+			}
+		}
+		if config.Flags&ORDER_BY > 0 && field.Tag.Get(tagKey) != "" {
+			if column := getColumnNameForField(field); config.authorized(c, column, ORDER_BY) {
+				capabilities.Orderable = append(capabilities.Orderable, column)
+			}
+		}
+	}
+	if config.Flags&SEARCH > 0 {
+		capabilities.Searchable = append(capabilities.Searchable, searchColumns(modelType, config)...)
+	}
+	if config.Flags&FILTER > 0 {
+		for name := range operators {
+			if name == regexOperator && !config.AllowRegex {
+				continue
+			}
+			capabilities.Operators = append(capabilities.Operators, name)
+		}
+		sort.Strings(capabilities.Operators)
+	}
+	return capabilities
+}
+
+// WriteCapabilities answers an OPTIONS request with the capabilities of model
+// as JSON.
+// Example:
+//
+//	router.OPTIONS("/users", func(c *gin.Context) {
+//		filter.WriteCapabilities(c, &UserModel{}, filter.Config{Flags: filter.ALL})
+//	})
+func WriteCapabilities(c *gin.Context, model interface{}, config Config) {
+	c.Header("Allow", "GET, HEAD, OPTIONS")
+	c.JSON(http.StatusOK, modelCapabilities(c, model, config))
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestWriteCapabilities checks the JSON body describing the model.
+func (s *TestSuite) TestWriteCapabilities() {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+
+	WriteCapabilities(ctx, &User{}, Config{Flags: SEARCH | FILTER | ORDER_BY})
+
+	s.Equal("GET, HEAD, OPTIONS", w.Header().Get("Allow"))
+	var capabilities Capabilities
+	s.NoError(json.Unmarshal(w.Body.Bytes(), &capabilities))
+	s.Equal([]string{"username", "email"}, capabilities.Filterable)
+	s.Equal([]string{"username", "full_name"}, capabilities.Searchable)
+	s.Equal([]string{"username", "full_name", "email"}, capabilities.Orderable)
+	s.Equal([]string{
+		"between", "ci", "date", "empty", "eq", "gt", "gte", "hasflag", "in", "iseq", "isnull",
+		"len_eq", "len_gt", "len_gte", "len_lt", "len_lte", "len_neq", "like", "lt", "lte", "neq", "notlike", "similar",
+	}, capabilities.Operators)
+}
+
+// TestModelCapabilitiesConfig checks that the disabled params, the denied
+// fields and the operators not allowed by the config are left out.
+func (s *TestSuite) TestModelCapabilitiesConfig() {
+	config := Config{
+		Flags:          FILTER | ORDER_BY,
+		DisabledParams: []string{"email"},
+		AllowRegex:     true,
+		AuthorizeField: func(c *gin.Context, field string, capability int) bool {
+			return field != "full_name" || capability != ORDER_BY
+		},
+	}
+
+	capabilities := ModelCapabilities(&User{}, config)
+	s.Equal([]string{"username"}, capabilities.Filterable)
+	s.Equal([]string{"username", "email"}, capabilities.Orderable)
+	s.Contains(capabilities.Operators, "regex")
+	s.NotContains(ModelCapabilities(&User{}, Config{Flags: FILTER}).Operators, "regex")
+}
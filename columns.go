@@ -0,0 +1,40 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// columnName matches the snake case names of the columns of Config.Columns.
+var columnName = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// columnsModel returns a model with a filterable field per column of columns,
+// for the scopes applied without model, eg : over a view. The columns which
+// are not snake case are left out.
+func columnsModel(columns map[string]reflect.Type) interface{} {
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := make([]reflect.StructField, 0, len(names))
+	for i, name := range names {
+		if columns[name] == nil || !columnName.MatchString(name) {
+			continue
+		}
+		fields = append(fields, reflect.StructField{
+			Name: "Column" + strconv.Itoa(i),
+			Type: columns[name],
+			Tag:  reflect.StructTag(`gorm:"column:` + name + `" filter:"filterable"`),
+		})
+	}
+	return reflect.New(reflect.StructOf(fields)).Interface()
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"reflect"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// TestFiltersViewColumns checks that a view without model is filtered and
+// ordered on the columns of the config, the other params being ignored.
+func (s *TestSuite) TestFiltersViewColumns() {
+	var rows []map[string]interface{}
+	ctx := newTestContext("status=paid&total__gte=100&secret=1&order_by=total")
+	config := Config{Flags: FILTER | ORDER_BY, Columns: map[string]reflect.Type{
+		"status": reflect.TypeOf(""),
+		"total":  reflect.TypeOf(0.0),
+	}}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "order_totals" WHERE "status" = \$1 AND "total" >= \$2 ORDER BY "order_totals"\."total" DESC$`).
+		WithArgs("paid", "100").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "total"}))
+	err := s.db.Table("order_totals").Scopes(FilterByConfig(ctx, config)).Find(&rows).Error
+	s.NoError(err)
+
+	config.Strict = true
+	_, err = ParseQuery(newTestContext("secret=1"), nil, config)
+	s.EqualError(err, "filter: secret: unknown filter")
+}
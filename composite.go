@@ -0,0 +1,93 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"strings"
+
+	"gorm.io/gorm/clause"
+)
+
+// combineComposites replaces the equality filters of each composite key of
+// config.CompositeFilters by a single row value comparison. The keys missing a
+// param are left as separate filters.
+func (config Config) combineComposites(filters []Filter) []Filter {
+	for _, params := range config.CompositeFilters {
+		indexes := make([]int, 0, len(params))
+		for _, param := range params {
+			if i := compositePart(filters, param); i >= 0 {
+				indexes = append(indexes, i)
+			}
+		}
+		if len(params) < 2 || len(indexes) != len(params) {
+			continue
+		}
+
+		var (
+			columns      []string
+			placeholders []string
+			vars         []interface{}
+			values       []string
+			parts        []Filter
+		)
+		for _, i := range indexes {
+			columns = append(columns, "?")
+			vars = append(vars, clause.Column{Name: filters[i].Column})
+			values = append(values, filters[i].Value)
+			parts = append(parts, filters[i])
+		}
+		for _, i := range indexes {
+			placeholders = append(placeholders, "?")
+			vars = append(vars, filters[i].arg(config, filters[i].Value))
+		}
+		composite := Filter{
+			Param:    strings.Join(params, ","),
+			Operator: "eq",
+			Value:    strings.Join(values, ","),
+			parts:    parts,
+			expr:     clause.Expr{SQL: "(" + strings.Join(columns, ", ") + ") = (" + strings.Join(placeholders, ", ") + ")", Vars: vars},
+		}
+
+		// The composite filter takes the place of its first part.
+		first := indexes[0]
+		for _, i := range indexes {
+			if i < first {
+				first = i
+			}
+		}
+		combined := make([]Filter, 0, len(filters)-len(indexes)+1)
+		for i, f := range filters {
+			if i == first {
+				combined = append(combined, composite)
+			} else if !containsIndex(indexes, i) {
+				combined = append(combined, f)
+			}
+		}
+		filters = combined
+	}
+	return filters
+}
+
+// compositePart returns the index of the equality filter of param which can
+// be part of a composite key, or -1.
+func compositePart(filters []Filter, param string) int {
+	for i, f := range filters {
+		if f.Param == param && f.Operator == "eq" && !f.OrNull && f.Column != "" &&
+			f.expr == nil && f.having == nil && len(f.jsonPath) == 0 && !f.hasPrecision {
+			return i
+		}
+	}
+	return -1
+}
+
+func containsIndex(indexes []int, index int) bool {
+	for _, i := range indexes {
+		if i == index {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type Warehouse struct {
+	Region string `filter:"filterable"`
+	Code   string `filter:"filterable"`
+	Name   string `filter:"filterable"`
+}
+
+// TestFiltersCompositeKey checks that the params of a composite key are
+// combined in a row value comparison.
+func (s *TestSuite) TestFiltersCompositeKey() {
+	var warehouses []Warehouse
+	ctx := newTestContext("region=EU&code=123&name=Main")
+	config := Config{Flags: FILTER, CompositeFilters: [][]string{{"region", "code"}}}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "warehouses" WHERE \("region", "code"\) = \(\$1, \$2\) AND "name" = \$3$`).
+		WithArgs("EU", "123", "Main").
+		WillReturnRows(sqlmock.NewRows([]string{"region", "code", "name"}))
+	err := s.db.Model(&Warehouse{}).Scopes(FilterByConfig(ctx, config)).Find(&warehouses).Error
+	s.NoError(err)
+}
+
+// TestFiltersIncompleteCompositeKey checks that a composite key missing a
+// param is filtered column by column.
+func (s *TestSuite) TestFiltersIncompleteCompositeKey() {
+	var warehouses []Warehouse
+	ctx := newTestContext("region=EU")
+	config := Config{Flags: FILTER, CompositeFilters: [][]string{{"region", "code"}}}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "warehouses" WHERE "region" = \$1$`).
+		WithArgs("EU").
+		WillReturnRows(sqlmock.NewRows([]string{"region", "code", "name"}))
+	err := s.db.Model(&Warehouse{}).Scopes(FilterByConfig(ctx, config)).Find(&warehouses).Error
+	s.NoError(err)
+}
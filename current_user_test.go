@@ -0,0 +1,43 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type Document struct {
+	Id      int64
+	Title   string `filter:"filterable"`
+	OwnerID int64  `filter:"filterable;current_user"`
+}
+
+// TestFiltersCurrentUser checks that "@me" is replaced by the context user id.
+func (s *TestSuite) TestFiltersCurrentUser() {
+	var documents []Document
+	ctx := newTestContext("owner_id=@me")
+	ctx.Set("user_id", 42)
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "documents" WHERE "owner_id" = \$1$`).
+		WithArgs("42").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "owner_id"}))
+	err := s.db.Model(&Document{}).Scopes(FilterByConfig(ctx, Config{Flags: FILTER, CurrentUserKey: "user_id"})).Find(&documents).Error
+	s.NoError(err)
+}
+
+// TestFiltersCurrentUserMissing checks "@me" without an authenticated user.
+func (s *TestSuite) TestFiltersCurrentUserMissing() {
+	ctx := newTestContext("owner_id=@me&title=@me")
+	config := Config{Flags: FILTER, CurrentUserKey: "user_id"}
+
+	query, err := ParseQuery(ctx, &Document{}, config)
+	s.NoError(err)
+	s.Equal([]Filter{{Param: "title", Column: "title", Operator: "eq", Value: "@me"}}, publicFilters(query.Filters))
+
+	config.Strict = true
+	_, err = ParseQuery(ctx, &Document{}, config)
+	s.EqualError(err, "filter: owner_id: no current user")
+}
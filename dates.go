@@ -0,0 +1,105 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func init() {
+	operators["date"] = dateOperator
+}
+
+// defaultTimeLayouts are the layouts of the values of the time fields when
+// Config.TimeLayouts is empty.
+var defaultTimeLayouts = []string{time.RFC3339Nano, time.DateOnly}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// isTime reports whether t, or the type it points to, is time.Time.
+func isTime(t reflect.Type) bool {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t == timeType
+}
+
+// parseTime parses value with the first matching layout of config. The
+// values without timezone are in the timezone of the client.
+func (config Config) parseTime(value string) (time.Time, error) {
+	location, err := time.LoadLocation(config.timezone())
+	if err != nil {
+		location = time.UTC
+	}
+	layouts := config.TimeLayouts
+	if len(layouts) == 0 {
+		layouts = defaultTimeLayouts
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, location); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("invalid time")
+}
+
+// timezone returns the timezone of the dates sent by the client.
+func (config Config) timezone() string {
+	if config.Timezone == "" {
+		return "UTC"
+	}
+	return config.Timezone
+}
+
+// withRequestTimezone returns config with the timezone stored in c under
+// config.TimezoneKey, if it is a valid timezone.
+func (config Config) withRequestTimezone(c *gin.Context) Config {
+	if config.TimezoneKey == "" || c == nil {
+		return config
+	}
+	timezone := c.GetString(config.TimezoneKey)
+	if timezone == "" {
+		return config
+	}
+	if _, err := time.LoadLocation(timezone); err == nil {
+		config.Timezone = timezone
+	}
+	return config
+}
+
+// validateDate checks that value is a date, eg : "2022-03-01", as expected by
+// the "date" operator.
+func validateDate(value string) error {
+	if _, err := time.Parse(time.DateOnly, value); err != nil {
+		return errors.New("invalid date")
+	}
+	return nil
+}
+
+// dateOperator compares the date of a timestamp stored in UTC, in the
+// timezone of the client, eg : "created_at__date=2022-03-01".
+func dateOperator(db *gorm.DB, config Config, f Filter) clause.Expression {
+	column := f.column(db)
+	switch db.Dialector.Name() {
+	case "postgres":
+		return clause.Expr{
+			SQL:  "(? AT TIME ZONE 'UTC' AT TIME ZONE ?)::date = ?",
+			Vars: []interface{}{column, config.timezone(), f.Value},
+		}
+	case "mysql":
+		return clause.Expr{
+			SQL:  "DATE(CONVERT_TZ(?, 'UTC', ?)) = ?",
+			Vars: []interface{}{column, config.timezone(), f.Value},
+		}
+	}
+	return clause.Expr{SQL: "DATE(?) = ?", Vars: []interface{}{column, f.Value}}
+}
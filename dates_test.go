@@ -0,0 +1,89 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type Event struct {
+	Id        int64
+	Name      string    `filter:"filterable"`
+	CreatedAt time.Time `filter:"filterable"`
+}
+
+// TestFiltersDateTimezone checks that dates are compared in the configured timezone.
+func (s *TestSuite) TestFiltersDateTimezone() {
+	var events []Event
+	ctx := newTestContext("created_at__date=2022-03-01")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "events" WHERE \("created_at" AT TIME ZONE 'UTC' AT TIME ZONE \$1\)::date = \$2$`).
+		WithArgs("Europe/Paris", "2022-03-01").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at"}))
+	err := s.db.Model(&Event{}).Scopes(FilterByConfig(ctx, Config{Flags: FILTER, Timezone: "Europe/Paris"})).Find(&events).Error
+	s.NoError(err)
+}
+
+// TestFiltersDateRequestTimezone checks that the timezone of the request context is used.
+func (s *TestSuite) TestFiltersDateRequestTimezone() {
+	var events []Event
+	ctx := newTestContext("created_at__date=2022-03-01")
+	ctx.Set("timezone", "America/New_York")
+	config := Config{Flags: FILTER, Timezone: "Europe/Paris", TimezoneKey: "timezone"}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "events" WHERE \("created_at" AT TIME ZONE 'UTC' AT TIME ZONE \$1\)::date = \$2$`).
+		WithArgs("America/New_York", "2022-03-01").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at"}))
+	err := s.db.Model(&Event{}).Scopes(FilterByConfig(ctx, config)).Find(&events).Error
+	s.NoError(err)
+
+	ctx.Set("timezone", "Mars/Olympus")
+	s.mock.ExpectQuery(`^SELECT \* FROM "events" WHERE \("created_at" AT TIME ZONE 'UTC' AT TIME ZONE \$1\)::date = \$2$`).
+		WithArgs("Europe/Paris", "2022-03-01").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at"}))
+	err = s.db.Model(&Event{}).Scopes(FilterByConfig(ctx, config)).Find(&events).Error
+	s.NoError(err)
+}
+
+// TestFiltersDateInvalid checks that the values which aren't dates are
+// reported in strict mode and ignored otherwise.
+func (s *TestSuite) TestFiltersDateInvalid() {
+	var events []Event
+	ctx := newTestContext("created_at__date=garbage&name=launch")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "events" WHERE "name" = \$1$`).
+		WithArgs("launch").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at"}))
+	err := s.db.Model(&Event{}).Scopes(FilterByConfig(ctx, Config{Flags: FILTER})).Find(&events).Error
+	s.NoError(err)
+
+	_, err = ParseQuery(ctx, &Event{}, Config{Flags: FILTER, Strict: true})
+	s.EqualError(err, "filter: created_at__date: invalid date")
+}
+
+// TestFiltersTimeValues checks that the values of the time fields are bound
+// as time.Time, from RFC 3339 and date-only values.
+func (s *TestSuite) TestFiltersTimeValues() {
+	var events []Event
+	ctx := newTestContext("created_at__gte=2022-03-01T08:30:00%2B01:00&created_at__lt=2022-04-01&created_at__lte=yesterday")
+	paris, err := time.LoadLocation("Europe/Paris")
+	s.Require().NoError(err)
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "events" WHERE "created_at" >= \$1 AND "created_at" < \$2$`).
+		WithArgs(time.Date(2022, 3, 1, 8, 30, 0, 0, paris), time.Date(2022, 4, 1, 0, 0, 0, 0, paris)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at"}))
+	err = s.db.Model(&Event{}).Scopes(FilterByConfig(ctx, Config{Flags: FILTER, Timezone: "Europe/Paris"})).Find(&events).Error
+	s.NoError(err)
+
+	_, err = ParseQuery(newTestContext("created_at__gt=01/03/2022"), &Event{}, Config{Flags: FILTER, Strict: true})
+	s.EqualError(err, "filter: created_at__gt: invalid time")
+
+	query, err := ParseQuery(newTestContext("created_at__gt=01/03/2022"), &Event{}, Config{Flags: FILTER, TimeLayouts: []string{"02/01/2006"}})
+	s.NoError(err)
+	s.Len(query.Filters, 1)
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultFilters returns the equality filters of the fields of modelType
+// tagged with a default value, eg : `filter:"filterable;default:active"`, or
+// with a value in config.DefaultFilters, whose param is not in sent. The
+// default values are bound like the values of the request, along with an
+// error if they are invalid.
+func defaultFilters(c *gin.Context, modelType reflect.Type, sent map[string]bool, config Config) ([]Filter, []error) {
+	var (
+		filters []Filter
+		errs    []error
+	)
+	for i := 0; i < modelType.NumField(); i++ {
+		field := modelType.Field(i)
+		param, column, ok := fieldParam(field)
+		if !ok {
+			continue
+		}
+		value, ok := config.defaultValue(field, param)
+		if !ok || sent[param] || config.disabled(param) || !config.authorized(c, param, FILTER) {
+			continue
+		}
+		filter := newFilter(field, param, column, "eq", false)
+		ok, err := filter.bind(c, value, config)
+		if err != nil {
+			errs = append(errs, &ParamError{Param: param, Reason: err.Error()})
+		}
+		if ok && err == nil {
+			filters = append(filters, filter)
+		}
+	}
+	return filters, errs
+}
+
+// defaultValue returns the default value of the field of param, from
+// config.DefaultFilters or from its tag according to
+// config.PreferTagDefaults.
+func (config Config) defaultValue(field reflect.StructField, param string) (string, bool) {
+	tagValue, hasTag := tagOption(field, "default")
+	value, hasValue := config.DefaultFilters[param]
+	if hasTag && (!hasValue || config.PreferTagDefaults) {
+		return tagValue, true
+	}
+	return value, hasValue
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"database/sql/driver"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type Campaign struct {
+	Id     int64
+	Name   string `filter:"filterable"`
+	Status string `filter:"filterable;default:active;any:all"`
+}
+
+// TestFiltersDefaultValue checks that the default value of a field applies
+// when its param is absent, and is overridden when it is sent.
+func (s *TestSuite) TestFiltersDefaultValue() {
+	var campaigns []Campaign
+	for query, expected := range map[string]struct {
+		where string
+		args  []driver.Value
+	}{
+		"":                       {` WHERE "status" = \$1`, []driver.Value{"active"}},
+		"name=spring":            {` WHERE "name" = \$1 AND "status" = \$2`, []driver.Value{"spring", "active"}},
+		"status=paused":          {` WHERE "status" = \$1`, []driver.Value{"paused"}},
+		"status__neq=draft":      {` WHERE "status" <> \$1`, []driver.Value{"draft"}},
+		"status=all&name=spring": {` WHERE "name" = \$1`, []driver.Value{"spring"}},
+	} {
+		s.mock.ExpectQuery(`^SELECT \* FROM "campaigns"` + expected.where + `$`).
+			WithArgs(expected.args...).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "status"}))
+		err := s.db.Model(&Campaign{}).Scopes(FilterByConfig(newTestContext(query), Config{Flags: FILTER})).Find(&campaigns).Error
+		s.NoError(err, query)
+	}
+}
+
+// TestFiltersDefaultPrecedence checks that the call-level default of a field
+// overrides the one of its tag, unless PreferTagDefaults is set.
+func (s *TestSuite) TestFiltersDefaultPrecedence() {
+	var campaigns []Campaign
+	for _, expected := range []struct {
+		config Config
+		args   []driver.Value
+	}{
+		{Config{Flags: FILTER, DefaultFilters: map[string]string{"status": "paused"}}, []driver.Value{"paused"}},
+		{Config{Flags: FILTER, DefaultFilters: map[string]string{"status": "paused"}, PreferTagDefaults: true}, []driver.Value{"active"}},
+	} {
+		s.mock.ExpectQuery(`^SELECT \* FROM "campaigns" WHERE "status" = \$1$`).
+			WithArgs(expected.args...).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "status"}))
+		err := s.db.Model(&Campaign{}).Scopes(FilterByConfig(newTestContext(""), expected.config)).Find(&campaigns).Error
+		s.NoError(err)
+	}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "campaigns" WHERE "name" = \$1$`).
+		WithArgs("spring").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "status"}))
+	config := Config{Flags: FILTER, DefaultFilters: map[string]string{"name": "spring", "status": "all"}}
+	err := s.db.Model(&Campaign{}).Scopes(FilterByConfig(newTestContext(""), config)).Find(&campaigns).Error
+	s.NoError(err)
+}
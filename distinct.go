@@ -0,0 +1,50 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// validateDistinctOn drops a "distinct_on" column which isn't allowed. In
+// strict mode, the column must be allowed and the requested order must start
+// with it.
+func (q *ParsedQuery) validateDistinctOn() error {
+	if q.Params.DistinctOn == "" {
+		return nil
+	}
+	var errs []error
+	if !contains(q.Config.DistinctOn, q.Params.DistinctOn) {
+		errs = append(errs, &ParamError{Param: "distinct_on", Reason: "column not allowed"})
+		q.Params.DistinctOn = ""
+	} else if q.Config.Flags&ORDER_BY > 0 && firstOrderColumn(q.Params) != q.Params.DistinctOn {
+		errs = append(errs, &ParamError{Param: "order_by", Reason: "must start with the distinct_on column"})
+	}
+	return q.Config.strictError(errs)
+}
+
+// distinct reports whether the rows of db are selected distinct, eg : with
+// "distinct=true" on a query joining a has many relation, which would repeat
+// the rows, or with a many-to-many relation filter.
+func (q *ParsedQuery) distinct(db *gorm.DB) bool {
+	switch q.Config.DistinctMode {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	return q.Params.Distinct && hasJoins(db) || joinsManyToMany(q.Filters)
+}
+
+// hasJoins reports whether db joins other tables.
+func hasJoins(db *gorm.DB) bool {
+	if len(db.Statement.Joins) > 0 {
+		return true
+	}
+	from, ok := db.Statement.Clauses["FROM"].Expression.(clause.From)
+	return ok && len(from.Joins) > 0
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// TestDistinctOn checks that the order is prefixed with the distinct column.
+func (s *TestSuite) TestDistinctOn() {
+	var users []User
+	ctx := newTestContext("distinct_on=email&order_by=id&limit=10")
+
+	s.mock.ExpectQuery(`^SELECT COUNT\(DISTINCT\("email"\)\) FROM "users"$`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	s.mock.ExpectQuery(`^SELECT DISTINCT ON \("users"\."email"\) "users"\.\* FROM "users" ORDER BY "users"\."email","users"\."id" DESC LIMIT 10$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err := s.db.Model(&User{}).Scopes(FilterByConfig(ctx, Config{Flags: ALL, DistinctOn: []string{"email"}})).Find(&users).Error
+	s.NoError(err)
+}
+
+// TestDistinctOnNotAllowed checks that only the allowed columns are used.
+func (s *TestSuite) TestDistinctOnNotAllowed() {
+	var users []User
+	ctx := newTestContext("distinct_on=password")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "users"$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err := s.db.Model(&User{}).Scopes(FilterByConfig(ctx, Config{Flags: FILTER, DistinctOn: []string{"email"}})).Find(&users).Error
+	s.NoError(err)
+}
+
+// TestDistinctOnStrictOrder checks that a mismatched order is reported in strict mode.
+func (s *TestSuite) TestDistinctOnStrictOrder() {
+	ctx := newTestContext("distinct_on=email&order_by=id")
+
+	_, err := ParseQuery(ctx, &User{}, Config{Flags: ORDER_BY, DistinctOn: []string{"email"}, Strict: true})
+	var paramErr *ParamError
+	s.Require().ErrorAs(err, &paramErr)
+	s.Equal("order_by", paramErr.Param)
+}
+
+// TestDistinctWithJoins checks that "distinct=true" is applied to the joined
+// queries, the count included.
+func (s *TestSuite) TestDistinctWithJoins() {
+	var users []User
+	ctx := newTestContext("distinct=true&limit=10")
+
+	s.mock.ExpectQuery(`^SELECT COUNT\(DISTINCT\("users"\."id"\)\) FROM "users" JOIN orders ON orders\.user_id = users\.id$`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	s.mock.ExpectQuery(`^SELECT DISTINCT "users"\.\* FROM "users" JOIN orders ON orders\.user_id = users\.id ORDER BY "users"\."created_at" DESC LIMIT 10$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err := s.db.Model(&User{}).Joins("JOIN orders ON orders.user_id = users.id").
+		Scopes(FilterByQuery(ctx, ALL)).Find(&users).Error
+	s.NoError(err)
+}
+
+// TestDistinctWithoutJoins checks that "distinct=true" is ignored without
+// joins, unless the config always applies it.
+func (s *TestSuite) TestDistinctWithoutJoins() {
+	var users []User
+	ctx := newTestContext("distinct=true")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "users"$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err := s.db.Model(&User{}).Scopes(FilterByQuery(ctx, FILTER)).Find(&users).Error
+	s.NoError(err)
+
+	s.mock.ExpectQuery(`^SELECT DISTINCT "users"\.\* FROM "users"$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err = s.db.Model(&User{}).Scopes(FilterByConfig(newTestContext(""), Config{Flags: FILTER, DistinctMode: "always"})).Find(&users).Error
+	s.NoError(err)
+
+	s.mock.ExpectQuery(`^SELECT "users"\."id",.* FROM "users" JOIN orders ON orders\.user_id = users\.id$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err = s.db.Model(&User{}).Joins("JOIN orders ON orders.user_id = users.id").
+		Scopes(FilterByConfig(ctx, Config{Flags: FILTER, DistinctMode: "never"})).Find(&users).Error
+	s.NoError(err)
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"errors"
+)
+
+// ParamError is reported in strict mode for a query param which can't be
+// applied.
+type ParamError struct {
+	Param  string
+	Reason string
+}
+
+func (e *ParamError) Error() string {
+	return "filter: " + e.Param + ": " + e.Reason
+}
+
+// strictError returns the error to report for errs, according to the strict
+// settings of config.
+func (config Config) strictError(errs []error) error {
+	if !config.Strict || len(errs) == 0 {
+		return nil
+	}
+	if config.CollectErrors {
+		return errors.Join(errs...)
+	}
+	return errs[0]
+}
+
+// withoutParams removes the errors of params.
+func withoutParams(errs []error, params []string) []error {
+	kept := errs[:0]
+	for _, err := range errs {
+		var paramErr *ParamError
+		if errors.As(err, &paramErr) && contains(params, paramErr.Param) {
+			continue
+		}
+		kept = append(kept, err)
+	}
+	return kept
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"errors"
+)
+
+// TestStrictFailFast checks that only the first invalid param is reported.
+func (s *TestSuite) TestStrictFailFast() {
+	ctx := newTestContext("password=secret&username__unknown=john&email=a@b.c")
+
+	_, err := ParseQuery(ctx, &User{}, Config{Flags: FILTER, Strict: true})
+	var paramErr *ParamError
+	s.Require().ErrorAs(err, &paramErr)
+	s.Equal("password", paramErr.Param)
+	s.Equal("filter: password: unknown filter", err.Error())
+}
+
+// TestStrictCollectErrors checks that every invalid param is reported.
+func (s *TestSuite) TestStrictCollectErrors() {
+	ctx := newTestContext("password=secret&username__unknown=john&email=a@b.c")
+
+	_, err := ParseQuery(ctx, &User{}, Config{Flags: FILTER, Strict: true, CollectErrors: true})
+	s.Require().Error(err)
+	joined, ok := err.(interface{ Unwrap() []error })
+	s.Require().True(ok)
+	var params []string
+	for _, err := range joined.Unwrap() {
+		var paramErr *ParamError
+		s.Require().True(errors.As(err, &paramErr))
+		params = append(params, paramErr.Param)
+	}
+	s.Equal([]string{"password", "username__unknown"}, params)
+}
+
+// TestStrictScopeError checks that the scope reports the error on the query.
+func (s *TestSuite) TestStrictScopeError() {
+	var users []User
+	ctx := newTestContext("password=secret")
+
+	err := s.db.Model(&User{}).Scopes(FilterByConfig(ctx, Config{Flags: FILTER, Strict: true})).Find(&users).Error
+	s.Error(err)
+	s.NoError(s.mock.ExpectationsWereMet())
+}
+
+// TestLenientIgnoresInvalid checks that invalid params are ignored by default.
+func (s *TestSuite) TestLenientIgnoresInvalid() {
+	ctx := newTestContext("password=secret&username__unknown=john&email=a@b.c")
+
+	query, err := ParseQuery(ctx, &User{}, Config{Flags: FILTER})
+	s.NoError(err)
+	s.Len(query.Filters, 1)
+}
+
+// TestStrictUnknownOperator checks that the unknown and the malformed
+// operator suffixes are ignored by default, and reported in strict mode.
+func (s *TestSuite) TestStrictUnknownOperator() {
+	for query, reason := range map[string]string{
+		"username__gte2=john":               "unknown operator gte2",
+		"username__=john":                   "malformed operator",
+		"username__GTE=john":                "unknown operator GTE",
+		"username__eq=john&password__gte=x": "unknown filter",
+	} {
+		parsed, err := ParseQuery(newTestContext(query), &User{}, Config{Flags: FILTER})
+		s.NoError(err, query)
+		for _, f := range parsed.Filters {
+			s.Equal("username", f.Param, query)
+		}
+
+		_, err = ParseQuery(newTestContext(query), &User{}, Config{Flags: FILTER, Strict: true})
+		var paramErr *ParamError
+		s.Require().ErrorAs(err, &paramErr, query)
+		s.Equal(reason, paramErr.Reason, query)
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ExplainFilter returns the SQL and the vars of the list query of model
+// filtered by the request with config, order and pagination included, without
+// running it. The pagination count is not run either, so the pagination
+// headers written on c report no rows. The SQL is empty if the query can't be
+// built, eg : an invalid param in strict mode.
+// Example:
+//
+//	sql, args := filter.ExplainFilter(c, db, &UserModel{}, filter.Config{Flags: filter.ALL})
+func ExplainFilter(c *gin.Context, db *gorm.DB, model interface{}, config Config) (string, []interface{}) {
+	rows := reflect.New(reflect.SliceOf(reflect.TypeOf(model))).Interface()
+	stmt := db.Session(&gorm.Session{DryRun: true}).Model(model).
+		Scopes(FilterByConfig(c, config)).Find(rows).Statement
+	if stmt.Error != nil {
+		return "", nil
+	}
+	return stmt.SQL.String(), stmt.Vars
+}
@@ -0,0 +1,20 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+// TestExplainFilter checks the SQL and vars of the whole query, without
+// running it.
+func (s *TestSuite) TestExplainFilter() {
+	ctx := newTestContext("username=sampleUser&order_by=email&order_direction=asc&page=2&limit=10")
+
+	sql, args := ExplainFilter(ctx, s.db, &User{}, Config{Flags: ALL})
+	s.Equal(`SELECT * FROM "users" WHERE "username" = $1 ORDER BY "users"."email" LIMIT 10 OFFSET 10`, sql)
+	s.Equal([]interface{}{"sampleUser"}, args)
+
+	sql, args = ExplainFilter(newTestContext("unknown=1"), s.db, &User{}, Config{Flags: FILTER, Strict: true})
+	s.Empty(sql)
+	s.Nil(args)
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+)
+
+// TestExportAll checks the truncation indicator under and over the export limit.
+func (s *TestSuite) TestExportAll() {
+	for count, truncated := range map[int]string{3: "false", 8: "true"} {
+		var users []User
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = &http.Request{
+			URL: &url.URL{
+				RawQuery: "all=true&page=2",
+			},
+		}
+
+		s.mock.ExpectQuery(`^SELECT count\(\*\) FROM "users"`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+		s.mock.ExpectQuery(`^SELECT \* FROM "users" LIMIT 5$`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+		err := s.db.Model(&User{}).Scopes(FilterByConfig(ctx, Config{Flags: PAGINATE, ExportLimit: 5})).Find(&users).Error
+		s.NoError(err)
+		s.Equal(truncated, w.Header().Get("X-Export-Truncated"))
+	}
+}
+
+// TestExportAllDisabled checks that all is ignored without an export limit.
+func (s *TestSuite) TestExportAllDisabled() {
+	var users []User
+	ctx := newTestContext("all=true")
+
+	s.mock.ExpectQuery(`^SELECT count\(\*\) FROM "users"`).WillReturnRows(sqlmock.NewRows([]string{"count"}))
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" LIMIT 20$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err := s.db.Model(&User{}).Scopes(FilterByQuery(ctx, PAGINATE)).Find(&users).Error
+	s.NoError(err)
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FacetValue is a distinct value of a field and its number of rows.
+type FacetValue struct {
+	Value interface{} `json:"value"`
+	Count int64       `json:"count"`
+}
+
+// Facet returns the distinct values of the filterable field of model named
+// param, with their number of rows matched by the filters and the search of
+// the request, eg : to fill a filter dropdown. The config of model is used if
+// it implements ModelConfigurer, without its pagination and order.
+// Example:
+//
+//	values, err := filter.Facet(c, db, &UserModel{}, "role")
+func Facet(c *gin.Context, db *gorm.DB, model interface{}, param string) ([]FacetValue, error) {
+	modelType := reflect.TypeOf(model)
+	if model == nil || modelType.Kind() != reflect.Ptr || modelType.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("filter: model must be a pointer to a struct")
+	}
+	filters := matchFilters(param, eq, modelType.Elem(), Config{})
+	if len(filters) == 0 || len(filters[0].jsonPath) > 0 {
+		return nil, &ParamError{Param: param, Reason: "unknown filter"}
+	}
+	config := Config{Flags: FILTER | SEARCH}
+	if configurer, ok := model.(ModelConfigurer); ok {
+		config = configurer.FilterConfig()
+	}
+	config.Flags &^= PAGINATE | ORDER_BY
+
+	column := clause.Column{Name: filters[0].Column}
+	rows, err := db.Model(model).Scopes(FilterByConfig(c, config)).
+		Select("? AS value, count(*) AS count", column).
+		Group(filters[0].Column).
+		Order("count DESC").
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []FacetValue
+	for rows.Next() {
+		var value FacetValue
+		if err := rows.Scan(&value.Value, &value.Count); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
@@ -0,0 +1,26 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// TestFacet checks that the distinct values of a field are counted with the
+// filters of the request.
+func (s *TestSuite) TestFacet() {
+	ctx := newTestContext("email=a@example.com&page=2")
+
+	s.mock.ExpectQuery(`^SELECT "username" AS value, count\(\*\) AS count FROM "users" WHERE "email" = \$1 GROUP BY "username" ORDER BY count DESC$`).
+		WithArgs("a@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"value", "count"}).AddRow("john", 3).AddRow("jane", 1))
+	values, err := Facet(ctx, s.db, &User{}, "username")
+	s.NoError(err)
+	s.Equal([]FacetValue{{Value: "john", Count: 3}, {Value: "jane", Count: 1}}, values)
+
+	_, err = Facet(ctx, s.db, &User{}, "password")
+	s.EqualError(err, "filter: password: unknown filter")
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const hasFlag = "hasflag"
+
+func init() {
+	operators[hasFlag] = func(db *gorm.DB, _ Config, f Filter) clause.Expression {
+		flag, _ := strconv.ParseUint(f.Value, 10, 64)
+		return clause.Expr{
+			SQL:  "(? & ?) = ?",
+			Vars: []interface{}{f.column(db), flag, flag},
+		}
+	}
+}
+
+// validateFlag checks that the filter is on an integer field and value is a
+// positive integer, as expected by the "hasflag" operator. A zero flag, which
+// every row has, is invalid.
+func (f Filter) validateFlag(value string) error {
+	if !isInteger(f.fieldType) {
+		return errors.New("not a bitmask field")
+	}
+	if flag, err := strconv.ParseUint(value, 10, 64); err != nil || flag == 0 {
+		return errors.New("invalid flag")
+	}
+	return nil
+}
+
+// isInteger reports whether t, or the type it points to, is an integer.
+func isInteger(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type Role struct {
+	Id          int64
+	Name        string `filter:"filterable"`
+	Permissions int    `filter:"filterable"`
+}
+
+// TestFiltersHasFlag checks the bitwise AND of the bitmask column.
+func (s *TestSuite) TestFiltersHasFlag() {
+	var roles []Role
+	ctx := newTestContext("permissions__hasflag=4")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "roles" WHERE \("permissions" & \$1\) = \$2$`).
+		WithArgs(4, 4).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "permissions"}))
+	err := s.db.Model(&Role{}).Scopes(FilterByQuery(ctx, FILTER)).Find(&roles).Error
+	s.NoError(err)
+}
+
+// TestFiltersHasFlagInvalid checks that the flag must be a positive integer of
+// an integer field, the invalid flags being ignored outside strict mode.
+func (s *TestSuite) TestFiltersHasFlagInvalid() {
+	for _, query := range []string{"permissions__hasflag=write", "permissions__hasflag=-1", "permissions__hasflag=0", "name__hasflag=4"} {
+		_, err := ParseQuery(newTestContext(query), &Role{}, Config{Flags: FILTER, Strict: true})
+		s.Error(err, query)
+	}
+
+	var roles []Role
+	s.mock.ExpectQuery(`^SELECT \* FROM "roles" WHERE "name" = \$1$`).
+		WithArgs("admin").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "permissions"}))
+	err := s.db.Model(&Role{}).Scopes(FilterByQuery(newTestContext("permissions__hasflag=0&name=admin"), FILTER)).Find(&roles).Error
+	s.NoError(err)
+}
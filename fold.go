@@ -0,0 +1,47 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func init() {
+	operators["ci"] = caseInsensitiveEqual
+}
+
+// removeAccents removes the diacritics of value, eg : "José" becomes "Jose".
+func removeAccents(value string) string {
+	unaccented, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), value)
+	if err != nil {
+		return value
+	}
+	return unaccented
+}
+
+// caseInsensitiveEqual compares the column to the value ignoring the case,
+// eg : "name__ci=Élodie". Postgres compares with ILIKE, following the
+// collation of the column, or with config.CaseInsensitiveCollation, the other
+// databases compare the lowered column to the lowered value, as LOWER keeps
+// the characters without simple lowercase, eg : "ß", which a case folding
+// would expand.
+func caseInsensitiveEqual(db *gorm.DB, config Config, f Filter) clause.Expression {
+	column := f.column(db)
+	if db.Dialector.Name() == "postgres" && config.CaseInsensitiveCollation != "" {
+		return clause.Expr{SQL: "? = ? COLLATE " + db.Statement.Quote(config.CaseInsensitiveCollation), Vars: []interface{}{column, f.Value}}
+	}
+	if db.Dialector.Name() == "postgres" {
+		return clause.Expr{SQL: "? ILIKE ?", Vars: []interface{}{column, likeEscaper.Replace(f.Value)}}
+	}
+	return clause.Expr{SQL: "LOWER(?) = ?", Vars: []interface{}{column, strings.ToLower(f.Value)}}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/gorm"
+)
+
+// TestFiltersCaseInsensitive checks the case insensitive comparison of a non-ASCII value.
+func (s *TestSuite) TestFiltersCaseInsensitive() {
+	var users []User
+	ctx := newTestContext("username__ci=%C3%89LODIE_%C4%B0")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE "username" ILIKE \$1$`).
+		WithArgs(`ÉLODIE\_İ`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err := s.db.Model(&User{}).Scopes(FilterByQuery(ctx, FILTER)).Find(&users).Error
+	s.NoError(err)
+}
+
+// TestFiltersCaseInsensitiveCollation checks that the case insensitive
+// comparison uses the configured collation on Postgres.
+func (s *TestSuite) TestFiltersCaseInsensitiveCollation() {
+	var users []User
+	ctx := newTestContext("username__ci=%C3%89LODIE_%C4%B0")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE "username" = \$1 COLLATE "ci"$`).
+		WithArgs(`ÉLODIE_İ`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err := s.db.Model(&User{}).Scopes(FilterByConfig(ctx, Config{Flags: FILTER, CaseInsensitiveCollation: "ci"})).Find(&users).Error
+	s.NoError(err)
+}
+
+// TestFiltersCaseInsensitiveLower checks that the other databases compare the
+// lowered column to the value lowered the same way.
+func (s *TestSuite) TestFiltersCaseInsensitiveLower() {
+	var users []User
+	db, err := gorm.Open(mysqlDialector{s.db.Dialector}, &gorm.Config{})
+	s.Require().NoError(err)
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE LOWER\("username"\) = \$1$`).
+		WithArgs("straße élodie").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err = db.Model(&User{}).Scopes(FilterByConfig(newTestContext("username__ci=Stra%C3%9Fe%20%C3%89LODIE"), Config{Flags: FILTER})).Find(&users).Error
+	s.NoError(err)
+}
+
+// mysqlDialector is the dialector of the tests which names itself "mysql" to
+// check the paths of the other databases.
+type mysqlDialector struct {
+	gorm.Dialector
+}
+
+func (mysqlDialector) Name() string {
+	return "mysql"
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type Invoice struct {
+	Id       int64
+	Price    float64
+	Quantity int
+	Total    float64 `gorm:"->;type:numeric GENERATED ALWAYS AS (price * quantity) STORED" filter:"filterable"`
+	Slug     string  `gorm:"->;column:search_slug;type:text GENERATED ALWAYS AS (lower(name)) STORED" filter:"filterable;searchable"`
+}
+
+// TestFiltersGeneratedColumn checks that the read-only generated columns can be filtered and searched.
+func (s *TestSuite) TestFiltersGeneratedColumn() {
+	var invoices []Invoice
+	ctx := newTestContext("total__gte=100&search_slug=acme&search=ac")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "invoices" WHERE \("search_slug" = \$1 AND "total" >= \$2\) AND "search_slug" LIKE \$3$`).
+		WithArgs("acme", "100", "%ac%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "price", "quantity", "total", "search_slug"}))
+	err := s.db.Model(&Invoice{}).Scopes(FilterByQuery(ctx, FILTER|SEARCH)).Find(&invoices).Error
+	s.NoError(err)
+
+	query, err := ParseQuery(ctx, &Invoice{}, Config{Flags: FILTER, Strict: true})
+	s.NoError(err)
+	s.Equal([]Filter{
+		{Param: "search_slug", Column: "search_slug", Operator: "eq", Value: "acme"},
+		{Param: "total", Column: "total", Operator: "gte", Value: "100"},
+	}, publicFilters(query.Filters))
+}
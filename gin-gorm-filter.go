@@ -6,28 +6,343 @@
 package filter
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
 	"net/url"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 type QueryParams struct {
-	Filter         string `form:"filter"`
-	Page           int    `form:"page,default=1"`
-	Limit          int    `form:"limit,default=20"`
-	All            bool   `form:"all,default=false"`
-	OrderBy        string `form:"order_by,default=created_at"`
-	OrderDirection string `form:"order_direction,default=desc,oneof=desc asc"`
+	Filter string `form:"filter"`
+	// The defaults are set by setDefault: default values in the form tags
+	// would override the custom defaults.
+	Page  int `form:"page"`
+	Limit int `form:"limit"`
+	// Offset is the number of rows skipped, instead of the ones of the
+	// previous pages, eg : "offset=40&limit=20".
+	Offset         int    `form:"offset"`
+	All            bool   `form:"all"`
+	OrderBy        string `form:"order_by"`
+	OrderDirection string `form:"order_direction,oneof=desc asc"`
+	OrderNulls     string `form:"order_nulls"`
+	// Sort is a shorthand for OrderBy and OrderDirection, eg : "-created_at".
+	Sort       string `form:"sort"`
+	Search     string `form:"search"`
+	WithCount  string `form:"with_count"`
+	DistinctOn string `form:"distinct_on"`
+	Distinct   bool   `form:"distinct"`
+}
+
+// Config holds the capabilities enabled for a scope and the knobs tuning them.
+type Config struct {
+	// Flags is the capability bitmask, e.g. FILTER|PAGINATE.
+	Flags int
+	// Defaults are used for the query params omitted by the client.
+	Defaults QueryParams
+	// DefaultFilters are the values of the filterable fields whose param is
+	// omitted by the client, eg : {"status": "active"}. They take precedence
+	// over the default values of the tags, unless PreferTagDefaults is set.
+	DefaultFilters map[string]string
+	// PreferTagDefaults applies the default value of the tag, eg :
+	// `filter:"filterable;default:active"`, rather than the one of
+	// DefaultFilters when both are set for a field.
+	PreferTagDefaults bool
+	// SearchFields restricts the global search to these columns. Only the
+	// fields tagged `searchable` can be part of the search.
+	SearchFields []string
+	// IndexedSearchOnly restricts the search to the fields tagged
+	// `searchable;indexed`, to avoid scanning the unindexed columns.
+	IndexedSearchOnly bool
+	// MaxSearchFields caps the number of columns of the search, the fields
+	// being kept by `priority`, eg : `filter:"searchable;priority:1"`, then
+	// in their order.
+	MaxSearchFields int
+	// FullTextSearch searches with the full text search of Postgres instead of
+	// LIKE. The mode is "plain" for plainto_tsquery, "websearch" for
+	// websearch_to_tsquery, or "tsquery" for to_tsquery with the words of the
+	// search only. The other databases keep LIKE.
+	FullTextSearch string
+	// SearchPrimaryKey also matches the integer primary key exactly when the
+	// search is an integer, eg : "search=42" adds OR "id" = 42.
+	SearchPrimaryKey bool
+	// UnaccentSearch ignores the accents in the search, eg : "jose" matches
+	// "José". Postgres compares with unaccent(), from the unaccent extension,
+	// on both sides, the other databases match the search without accents.
+	UnaccentSearch bool
+	// CaseInsensitiveCollation is the collation of the "__ci" filters on
+	// Postgres instead of ILIKE, eg : "ci" for "name" = $1 COLLATE "ci". It
+	// must be a nondeterministic collation to ignore the case, eg : CREATE
+	// COLLATION ci (provider = icu, locale = 'und-u-ks-level2', deterministic =
+	// false).
+	CaseInsensitiveCollation string
+	// SkipImpliedSearch leaves out the search when an equality filter on a
+	// searched column implies it, eg : "username=john&search=john", instead
+	// of ANDing both. The full text search is always applied.
+	SkipImpliedSearch bool
+	// DecimalSeparator and GroupingSeparator are the separators of the numbers
+	// sent for numeric fields, eg : "," and "." for "1.234,56". The numbers
+	// are used as is if DecimalSeparator is empty.
+	DecimalSeparator  string
+	GroupingSeparator string
+	// StripCurrency accepts amounts on numeric fields and on the fields tagged
+	// `decimal`, eg : "$1,234.50". The currency symbols and the spaces are
+	// removed, and "," is the grouping separator if DecimalSeparator is empty.
+	StripCurrency bool
+	// NormalizeValues cleans the values sent by the clients before binding
+	// them : the values encoded twice are decoded, and the surrounding spaces,
+	// the surrounding quotes and the trailing slashes are removed, eg :
+	// `"john"`, "john/" or "john%2520doe".
+	NormalizeValues bool
+	// NullValues matches the NULL columns of the nullable fields, pointers or
+	// sql.Null types, with the "null" value, eg : "deleted_by=null" filters on
+	// IS NULL. The value is kept as is for the other fields.
+	NullValues bool
+	// ReadMethodsOnly applies the scope to the GET and HEAD requests only, the
+	// query params of the other requests, eg : POST, being ignored.
+	ReadMethodsOnly bool
+	// PolymorphicOwners are the models declaring a polymorphic association
+	// with the filtered model, eg : []interface{}{&Post{}} for a Comment
+	// belonging to a Post through `gorm:"polymorphic:Commentable"`.
+	PolymorphicOwners []interface{}
+	// ExportLimit is the maximum number of rows returned when the client asks
+	// for every row with "all=true". The all param is ignored if it is 0.
+	ExportLimit int
+	// RangeUnit enables the pagination by the Range header of the request
+	// with this unit, eg : "items" for "Range: items=0-24". It wins over the
+	// page and limit params.
+	RangeUnit string
+	// RangeNotSatisfiable reports a Range starting after the last row with
+	// ErrRangeNotSatisfiable instead of returning the last rows.
+	RangeNotSatisfiable bool
+	// Timezone is the timezone of the dates sent by the clients, used to
+	// compare timestamps stored in UTC by date. It defaults to UTC.
+	Timezone string
+	// TimezoneKey is the gin context key holding the timezone of the request,
+	// eg : set by a locale middleware. It overrides Timezone when set.
+	TimezoneKey string
+	// TimeLayouts are the layouts accepted for the values of the time.Time
+	// fields, which are bound as time.Time. They default to RFC 3339 and
+	// "2006-01-02", the values without timezone being in Timezone.
+	TimeLayouts []string
+	// WithCount lists the has-many relations whose rows can be counted with
+	// "with_count={relation}", eg : []string{"orders"}, or filtered by count
+	// with "{relation}__count_gte={count}" if FILTER is enabled.
+	WithCount []string
+	// HavingCount applies the relation count filters with a join of the
+	// relation, the rows being grouped by primary key and the count compared
+	// in the HAVING clause, instead of a subquery per row.
+	HavingCount bool
+	// ManyToMany lists the many-to-many relations whose filterable fields can
+	// be filtered with "{relation}.{param}", eg : []string{"roles"} for
+	// "roles.name=admin". The join table and the relation are joined and the
+	// rows are selected distinct, unless DistinctMode is "never".
+	ManyToMany []string
+	// Percentiles lists the numeric params which can be filtered by
+	// percentile, eg : "score__percentile_gt=0.9" for the top 10% of the rows
+	// by score. The percentiles are computed with percent_rank() over all the
+	// rows of the table.
+	Percentiles []string
+	// MaxOrderColumns caps the number of comma separated columns of the
+	// order, eg : "order_by=name,created_at". The extra columns are dropped.
+	MaxOrderColumns int
+	// MaxIdentifierLength is the maximum length of the columns sent by the
+	// clients, eg : in "order_by". It defaults to 63, the limit of Postgres.
+	MaxIdentifierLength int
+	// OrNullsLast places the NULL values last when ordering by a column with
+	// an or-null filter, eg : "score__gte_or_null=10&order_by=score", whatever
+	// the order direction.
+	OrNullsLast bool
+	// NoOrder is the "{param}={value}" query param disabling the order of a
+	// request, eg : "order=none" for the exports. The default order is not
+	// applied either.
+	NoOrder string
+	// ReverseParam is the boolean query param inverting the direction of
+	// every order column, eg : "reverse" for "reverse=true".
+	ReverseParam string
+	// PreferOrderBy applies the "order_by" param rather than "sort" when the
+	// client sends both.
+	PreferOrderBy bool
+	// PreferPage applies the "page" param rather than "offset" when the
+	// client sends both.
+	PreferPage bool
+	// DistinctOn lists the columns allowed for "distinct_on={column}", which
+	// fetches the first row of each group on Postgres.
+	DistinctOn []string
+	// DistinctMode is "always" to select distinct rows for every request, or
+	// "never" to ignore the "distinct=true" param. By default, the param is
+	// applied to the queries having joins only.
+	DistinctMode string
+	// CoercePrimaryKey converts the values of the primary key filters to the
+	// type of the key, eg : int64. The values which can't be converted are
+	// dropped.
+	CoercePrimaryKey bool
+	// ExpressionAliases maps params to static SQL conditions by value, eg :
+	// {"active": {"true": "deleted_at IS NULL AND banned = false"}}. No value
+	// of the request is interpolated in the conditions.
+	ExpressionAliases map[string]map[string]string
+	// RawConditions maps params to the builders of their SQL condition, eg :
+	// {"overdue": ...} for "overdue=30". The values of the request are bound
+	// as vars by the builders.
+	RawConditions map[string]RawCondition
+	// ExistsFilters maps boolean params to static subqueries, eg : {"is_vip":
+	// "SELECT 1 FROM payments WHERE payments.user_id = users.id HAVING
+	// sum(amount) > 1000"}. "is_vip=true" matches the rows for which the
+	// subquery returns a row, and "is_vip=false" the others.
+	ExistsFilters map[string]string
+	// CompositeFilters are the params of the composite keys, eg :
+	// [][]string{{"region", "code"}}. Their equality filters are combined in
+	// a single row value comparison when all of them are sent, eg :
+	// "region=EU&code=123" filters on ("region","code") = ('EU','123').
+	CompositeFilters [][]string
+	// BooleanCombinations maps params to the combination of two boolean
+	// columns they filter on, eg : {"only_one_flag": {Left: "a", Right: "b",
+	// Operator: "xor"}} for "only_one_flag=true".
+	BooleanCombinations map[string]BooleanCombination
+	// RangeOverlaps maps params to the columns bounding the range of the
+	// rows, eg : {"overlaps": {Start: "start_at", End: "end_at"}} for
+	// "overlaps=2022-03-01,2022-03-10", which matches the rows whose range
+	// overlaps the given one.
+	RangeOverlaps map[string]RangeOverlap
+	// BetweenColumns lists the columns which can bound a value with the
+	// "{value}__between_cols={min column},{max column}" filters, eg :
+	// "30__between_cols=min_age,max_age" for "min_age" <= 30 AND 30 <=
+	// "max_age". The value must be a number or a time.
+	BetweenColumns []string
+	// Columns are the filterable columns and their types when the scope is
+	// applied without model, eg : db.Table("order_totals") over a view or a
+	// CTE, with {"status": reflect.TypeOf(""), "total": reflect.TypeOf(0.0)}.
+	// The other params are unknown filters.
+	Columns map[string]reflect.Type
+	// MetaAppliedFilters includes the applied filters in the response meta.
+	MetaAppliedFilters bool
+	// RewriteFilter is called with the param, operator and value of each
+	// filter, the values of the list operators being joined with commas. It
+	// returns the operator and value to apply, or false to reject the filter.
+	RewriteFilter func(param, operator, value string) (string, string, bool)
+	// ArrayBinding binds the values of the "in" filters as a single array on
+	// Postgres, eg : "id" = ANY($1), instead of a placeholder per value.
+	ArrayBinding bool
+	// StableStatements keeps the SQL of a request the same whatever its
+	// values, for the prepared statement caches : the "in" filters are bound
+	// as arrays on Postgres and the limit and offset are bound as vars, eg :
+	// LIMIT $1 OFFSET $2.
+	StableStatements bool
+	// AllowRegex enables the "{param}__regex={pattern}" filters, eg :
+	// "name" ~ $1 on Postgres and "name" REGEXP ? on MySQL. They are
+	// disabled by default as they can't use the indexes.
+	AllowRegex bool
+	// AuthorizeField is called with the request, the param of each filter and
+	// the column of each order with the FILTER or ORDER_BY capability, eg :
+	// to let only the managers order by "salary". The denied fields are
+	// ignored, unless Strict is set.
+	AuthorizeField func(c *gin.Context, field string, capability int) bool
+	// IgnoreEmptyLists ignores the list filters left without values, eg :
+	// "owner_id__in=@me" without authenticated user, instead of matching no
+	// rows.
+	IgnoreEmptyLists bool
+	// FilterReservedParams lists the reserved params, eg : "search" or "page",
+	// which are filters for the model. They lose their usual meaning.
+	FilterReservedParams []string
+	// DisabledParams lists the params treated as unknown filters, eg : to
+	// disable a filter of an endpoint without changing the tags of the model.
+	DisabledParams []string
+	// CurrentUserKey is the gin context key holding the id of the
+	// authenticated user, which replaces the "@me" value on the fields tagged
+	// `current_user`.
+	CurrentUserKey string
+	// OnBuild is called with the time spent by the scope to parse the request
+	// and add its clauses to the query. The count query of the pagination runs
+	// later, when the query is executed, and is not included.
+	OnBuild func(c *gin.Context, elapsed time.Duration)
+	// OnQuery is called with the summary of the capabilities applied once the
+	// query is built, eg : to feed the usage metrics of an endpoint.
+	OnQuery func(c *gin.Context, summary QuerySummary)
+	// Strict makes the scope fail with a *ParamError for the params it can't
+	// apply instead of ignoring them.
+	Strict bool
+	// CollectErrors makes a strict scope report every invalid param, joined
+	// with errors.Join, instead of only the first one.
+	CollectErrors bool
+
+	// db is the database of the scope, whose naming strategy and cache parse
+	// the schemas.
+	db *gorm.DB
+}
+
+// Filter is a single "{param}{operator}{value}" condition matched against a
+// filterable field.
+type Filter struct {
+	Param    string
+	Column   string
+	Operator string
+	Value    string
+	// Values are the values of the list operators, eg : "in".
+	Values []string
+	// OrNull also matches the rows where the column is NULL.
+	OrNull bool
+
+	any         string
+	hasAny      bool
+	currentUser bool
+	primaryKey  bool
+	fieldType   reflect.Type
+
+	decimal    bool
+	softDelete bool
+	having     *havingCount
+	// table qualifies the column, eg : the table of the relation of a
+	// many-to-many relation filter.
+	table string
+	// joins are the joins of the join table and the relation of a
+	// many-to-many relation filter.
+	joins        []clause.Expr
+	precision    int
+	hasPrecision bool
+	// expr is the condition of the filters which are not on a column, eg : an
+	// expression alias.
+	expr clause.Expression
+	// jsonPath are the keys of the filtered subfield of a JSON column.
+	jsonPath []string
+	// parts are the filters combined in a composite key filter.
+	parts []Filter
+	// arrayMode is the semantics of the equality filters of an array field,
+	// arrayContains or arrayOverlaps.
+	arrayMode string
+}
+
+// ParsedQuery is the normalized state of a request once its query params have
+// been bound and matched against the model.
+type ParsedQuery struct {
+	Params  QueryParams
+	Filters []Filter
+	// Where is the parsed "where" expression, if any.
+	Where *FilterGroup
+	// SearchColumns are the columns matched against Params.Search.
+	SearchColumns []string
+	Config        Config
+
+	// items is the number of rows counted for the pagination.
+	items int64
+	// searchKey is the primary key equality added to the search if
+	// Config.SearchPrimaryKey is set and the search is a key.
+	searchKey clause.Expression
+	// where is the "where" query param parsed in Where.
+	where string
 }
 
 const (
-	//SEARCH   = 1  // NOT IMPLEMENTED // Filter response with LIKE query "search={search_phrase}"
+	SEARCH   = 1  // Filter response with LIKE query "search={search_phrase}"
 	FILTER   = 2  // Filter response by column name values "{column_name}={value}"
 	PAGINATE = 4  // Paginate response with page and page_size
 	ORDER_BY = 8  // Order response by column name
@@ -35,16 +350,251 @@ const (
 	tagKey   = "filter"
 )
 
+// reservedParams are the query params which are never treated as filters.
+var reservedParams = map[string]bool{
+	"limit":           true,
+	"page":            true,
+	"offset":          true,
+	"order_by":        true,
+	"order_direction": true,
+	"order_nulls":     true,
+	"sort":            true,
+	"search":          true,
+	"all":             true,
+	"with_count":      true,
+	"distinct_on":     true,
+	"distinct":        true,
+	"where":           true,
+	"desc":            true,
+}
+
 var (
 	columnNameRegexp = regexp.MustCompile(`(?m)column:(\w{1,}).*`)
 	paramNameRegexp  = regexp.MustCompile(`(?m)param:(\w{1,}).*`)
 )
 
-func orderBy(db *gorm.DB, params QueryParams, table string) *gorm.DB {
-	return db.Order(clause.OrderByColumn{
-		Column: clause.Column{Name: table + "." + params.OrderBy},
-		Desc:   params.OrderDirection == "desc"},
+// applySort sets the order from the "sort" param, eg : "sort=-created_at"
+// orders by created_at desc. The "sort" param wins over "order_by" unless
+// preferOrderBy is set and the client sent "order_by".
+func applySort(p *QueryParams, values url.Values, preferOrderBy bool) {
+	if p.Sort == "" || preferOrderBy && values.Has("order_by") {
+		return
+	}
+	if strings.Contains(p.Sort, ",") {
+		// Each column keeps its own direction, eg : "-score,name".
+		p.OrderBy, p.OrderDirection = p.Sort, "asc"
+		return
+	}
+	column, desc := strings.CutPrefix(p.Sort, "-")
+	p.OrderBy, p.OrderDirection = column, "asc"
+	if desc {
+		p.OrderDirection = "desc"
+	}
+}
+
+// applyOffset sets the page of the "offset" param, eg : "offset=40&limit=20"
+// is the third page. The "offset" param wins over "page" unless preferPage is
+// set and the client sent "page", the offset being then ignored.
+func applyOffset(p *QueryParams, values url.Values, preferPage bool) {
+	if !values.Has("offset") || preferPage && values.Has("page") {
+		p.Offset = 0
+		return
+	}
+	if p.Offset < 0 {
+		p.Offset = 0
+	}
+	p.Page = p.Offset/p.Limit + 1
+}
+
+// noOrderParam returns the param of config.NoOrder, eg : "order".
+func (config Config) noOrderParam() string {
+	param, _, _ := strings.Cut(config.NoOrder, "=")
+	return param
+}
+
+// noOrder reports whether values hold the config.NoOrder param and value.
+func (config Config) noOrder(values url.Values) bool {
+	param, value, ok := strings.Cut(config.NoOrder, "=")
+	return ok && values.Has(param) && values.Get(param) == value
+}
+
+// reverseOrder reports whether values hold a true config.ReverseParam.
+func (config Config) reverseOrder(values url.Values) bool {
+	if config.ReverseParam == "" {
+		return false
+	}
+	reverse, _ := strconv.ParseBool(values.Get(config.ReverseParam))
+	return reverse
+}
+
+// reverseOrder inverts the direction of every column of the order of p, the
+// direction being then written on each column, eg : "-score,name".
+func reverseOrder(p *QueryParams) {
+	var names []string
+	for _, column := range orderColumns(*p) {
+		name := column.name
+		if column.inOrder {
+			name += inOrderSuffix
+		}
+		if column.desc {
+			names = append(names, name)
+		} else {
+			names = append(names, "-"+name)
+		}
+	}
+	p.OrderBy, p.OrderDirection = strings.Join(names, ","), "asc"
+}
+
+// orderColumn is a column of the order. An inOrder column is ordered by the
+// position of its values in its "in" filter.
+type orderColumn struct {
+	name    string
+	desc    bool
+	inOrder bool
+}
+
+// orderColumns returns the comma separated columns of params.OrderBy. A column
+// prefixed with "-" is ordered desc, the others in params.OrderDirection.
+func orderColumns(params QueryParams) []orderColumn {
+	var columns []orderColumn
+	for _, name := range strings.Split(params.OrderBy, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || name == "-" {
+			continue
+		}
+		column := orderColumn{name: name, desc: params.OrderDirection == "desc"}
+		if trimmed, ok := strings.CutPrefix(name, "-"); ok {
+			column = orderColumn{name: trimmed, desc: true}
+		}
+		column.name, column.inOrder = strings.CutSuffix(column.name, inOrderSuffix)
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+// firstOrderColumn returns the name of the first column of the order.
+func firstOrderColumn(params QueryParams) string {
+	if columns := orderColumns(params); len(columns) > 0 {
+		return columns[0].name
+	}
+	return ""
+}
+
+// limitOrderColumns drops the order columns over config.MaxOrderColumns. It
+// reports them in strict mode.
+func (q *ParsedQuery) limitOrderColumns() error {
+	if q.Config.MaxOrderColumns <= 0 || q.Config.Flags&ORDER_BY == 0 {
+		return nil
+	}
+	names := strings.Split(q.Params.OrderBy, ",")
+	if len(names) <= q.Config.MaxOrderColumns {
+		return nil
+	}
+	q.Params.OrderBy = strings.Join(names[:q.Config.MaxOrderColumns], ",")
+	return q.Config.strictError([]error{&ParamError{Param: "order_by", Reason: "too many order columns"}})
+}
+
+// authorized reports whether config.AuthorizeField allows field for
+// capability.
+func (config Config) authorized(c *gin.Context, field string, capability int) bool {
+	return config.AuthorizeField == nil || config.AuthorizeField(c, field, capability)
+}
+
+// authorizeOrderColumns drops the order columns denied by
+// config.AuthorizeField. It reports them in strict mode.
+func (q *ParsedQuery) authorizeOrderColumns(c *gin.Context) error {
+	if q.Config.AuthorizeField == nil || q.Config.Flags&ORDER_BY == 0 {
+		return nil
+	}
+	var (
+		names []string
+		errs  []error
+	)
+	for _, name := range strings.Split(q.Params.OrderBy, ",") {
+		column := strings.TrimPrefix(strings.TrimSpace(name), "-")
+		column = strings.TrimSuffix(column, inOrderSuffix)
+		if column != "" && !q.Config.authorized(c, column, ORDER_BY) {
+			errs = append(errs, &ParamError{Param: "order_by", Reason: "unauthorized column " + column})
+			continue
+		}
+		names = append(names, name)
+	}
+	q.Params.OrderBy = strings.Join(names, ",")
+	return q.Config.strictError(errs)
+}
+
+// orderParams returns the params of the order, the NULL values being placed
+// last when config.OrNullsLast is set and an ordered column has an or-null
+// filter, unless the client sent "order_nulls".
+func (q *ParsedQuery) orderParams() QueryParams {
+	params := q.Params
+	if !q.Config.OrNullsLast || params.OrderNulls != "" {
+		return params
+	}
+	for _, column := range orderColumns(params) {
+		for _, f := range q.Filters {
+			if f.OrNull && f.Column == column.name && len(f.jsonPath) == 0 {
+				params.OrderNulls = "last"
+				return params
+			}
+		}
+	}
+	return params
+}
+
+func orderBy(db *gorm.DB, params QueryParams, table string, filters []Filter, config Config) *gorm.DB {
+	var (
+		columns     []clause.OrderByColumn
+		expressions []clause.Expression
+		inOrder     bool
 	)
+	for _, column := range orderColumns(params) {
+		if column.inOrder {
+			if expression := inOrderExpression(db, column, table, filters, config); expression != nil {
+				expressions = append(expressions, expression)
+				inOrder = true
+				continue
+			}
+		}
+		orderColumn := clause.OrderByColumn{
+			Column: clause.Column{Name: table + "." + column.name},
+			Desc:   column.desc,
+		}
+		if (params.OrderNulls == "first" || params.OrderNulls == "last") && db.Dialector.Name() != "postgres" {
+			// The other databases, eg : MySQL, have no NULLS FIRST/LAST: the
+			// rows are ordered by whether the column is NULL beforehand.
+			quoted := db.Statement.Quote(clause.Column{Name: table + "." + column.name})
+			nullColumn := clause.OrderByColumn{
+				Column: clause.Column{Name: quoted + " IS NULL", Raw: true},
+				Desc:   params.OrderNulls == "first",
+			}
+			columns = append(columns, nullColumn)
+			expressions = append(expressions, orderColumnExpression(nullColumn))
+		} else if params.OrderNulls == "first" || params.OrderNulls == "last" {
+			// NULLS FIRST/LAST goes after the direction, so the direction is
+			// part of the raw (already quoted) column.
+			direction := "ASC"
+			if column.desc {
+				direction = "DESC"
+			}
+			quoted := db.Statement.Quote(clause.Column{Name: table + "." + column.name})
+			orderColumn = clause.OrderByColumn{
+				Column: clause.Column{
+					Name: quoted + " " + direction + " NULLS " + strings.ToUpper(params.OrderNulls),
+					Raw:  true,
+				},
+			}
+		}
+		columns = append(columns, orderColumn)
+		expressions = append(expressions, orderColumnExpression(orderColumn))
+	}
+	if inOrder {
+		return orderByExpressions(db, expressions)
+	}
+	for _, column := range columns {
+		db = db.Order(column)
+	}
+	return db
 }
 
 var matchFirstCap = regexp.MustCompile("(.)([A-Z][a-z]+)")
@@ -65,69 +615,475 @@ func getColumnNameForField(field reflect.StructField) string {
 	return ToSnakeCase(field.Name)
 }
 
-func filterField(field reflect.StructField, key string, value string, separator string) clause.Expression {
-	var paramName string
+// tagOption returns the value of the "{name}:{value}" option of the `filter`
+// tag of field.
+func tagOption(field reflect.StructField, name string) (string, bool) {
+	for _, option := range strings.Split(field.Tag.Get(tagKey), ";") {
+		key, value, found := strings.Cut(option, ":")
+		if found && strings.TrimSpace(key) == name {
+			return strings.TrimSpace(value), true
+		}
+	}
+	return "", false
+}
+
+// hasTagFlag reports whether the `filter` tag of field has the name option.
+func hasTagFlag(field reflect.StructField, name string) bool {
+	for _, option := range strings.Split(field.Tag.Get(tagKey), ";") {
+		if strings.TrimSpace(option) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldParam returns the query param and column used to filter on field, or
+// false if the field is not filterable.
+func fieldParam(field reflect.StructField) (string, string, bool) {
 	if !strings.Contains(field.Tag.Get(tagKey), "filterable") {
-		return nil
+		return "", "", false
 	}
+	// The param only renames the query param, the column is the one of the
+	// field, as searched.
 	columnName := getColumnNameForField(field)
+	param := columnName
 	paramMatch := paramNameRegexp.FindStringSubmatch(field.Tag.Get(tagKey))
-
 	if len(paramMatch) == 2 {
-		paramName = paramMatch[1]
-		columnName = paramName
-	} else {
-		paramName = columnName
+		param = paramMatch[1]
 	}
+	return param, columnName, true
+}
 
-	if paramName != key {
-		return nil
+func parseFilters(c *gin.Context, values url.Values, modelType reflect.Type, config Config) ([]Filter, []error) {
+	values = compactArrays(values)
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if key == config.noOrderParam() && key != "" || key == config.ReverseParam && key != "" {
+			continue
+		}
+		if !reservedParams[key] || contains(config.FilterReservedParams, key) {
+			keys = append(keys, key)
+		}
 	}
+	sort.Strings(keys)
 
-	switch separator {
-	case eq:
-		return clause.Eq{Column: columnName, Value: value}
-	case neq:
-		return clause.Neq{Column: columnName, Value: value}
-	case gt:
-		return clause.Gt{Column: columnName, Value: value}
-	case gte:
-		return clause.Gte{Column: columnName, Value: value}
-	case lt:
-		return clause.Lt{Column: columnName, Value: value}
-	case lte:
-		return clause.Lte{Column: columnName, Value: value}
-	}
-
-	return nil
-}
-
-func expressionByField(
-	db *gorm.DB, values url.Values, modelType reflect.Type,
-) *gorm.DB {
-	numFields := modelType.NumField()
-	expressions := make([]clause.Expression, 0, numFields)
-	for key, array := range values {
-		if key != "limit" && key != "page" && key != "order_by" && key != "desc" {
-			for _, value := range array {
-				key, value, separator := getSeparator(key, value)
-				for i := 0; i < numFields; i++ {
-					field := modelType.Field(i)
-					expression := filterField(field, key, value, separator)
-					if expression != nil {
-						expressions = append(expressions, expression)
+	var (
+		filters []Filter
+		errs    []error
+		// sent are the params of the fields sent by the client, whose
+		// default values are not applied.
+		sent = map[string]bool{}
+	)
+	for _, rawKey := range keys {
+		for i, value := range values[rawKey] {
+			filter, ok, err := config.aliasFilter(rawKey, value)
+			if !ok {
+				filter, ok, err = config.rawConditionFilter(c, rawKey, value)
+			}
+			if !ok {
+				filter, ok, err = config.existsFilter(rawKey, value)
+			}
+			if !ok {
+				filter, ok, err = config.booleanCombinationFilter(rawKey, value)
+			}
+			if !ok {
+				filter, ok, err = config.rangeOverlapFilter(rawKey, value)
+			}
+			if !ok {
+				filter, ok, err = config.betweenColumnsFilter(rawKey, value)
+			}
+			if !ok {
+				filter, ok, err = config.relationCountFilter(rawKey, value, modelType)
+			}
+			if !ok {
+				filter, ok, err = config.percentileFilter(rawKey, value, modelType)
+			}
+			if !ok {
+				filter, ok, err = config.manyToManyFilter(c, rawKey, value, values[rawKey], modelType)
+			}
+			if ok && (config.disabled(rawKey) || err == nil && config.disabled(filter.Param)) {
+				errs = append(errs, &ParamError{Param: rawKey, Reason: "unknown filter"})
+				continue
+			}
+			if ok {
+				if err == nil && !config.authorized(c, filter.Param, FILTER) {
+					err = errors.New("unauthorized filter")
+				}
+				if err != nil {
+					errs = append(errs, &ParamError{Param: rawKey, Reason: err.Error()})
+				} else if filter.Operator != "" {
+					// The filters without operator match every row.
+					filters = append(filters, filter)
+				}
+				continue
+			}
+			key, value, separator := getSeparator(rawKey, value)
+			matched := config.withoutDisabled(matchFilters(key, separator, modelType, config))
+			if len(matched) == 0 {
+				reason := "unknown filter"
+				if _, _, _, err := parseOperator(key, separator); err != nil {
+					reason = err.Error()
+				}
+				errs = append(errs, &ParamError{Param: rawKey, Reason: reason})
+			}
+			for _, filter := range matched {
+				var (
+					ok  bool
+					err error
+				)
+				sent[filter.Param] = true
+				if !config.authorized(c, filter.Param, FILTER) {
+					errs = append(errs, &ParamError{Param: rawKey, Reason: "unauthorized filter"})
+					continue
+				}
+				if listOperators[filter.Operator] {
+					if i > 0 {
+						// Every value has been bound with the first one.
+						continue
 					}
+					ok, err = filter.bindList(c, splitList(values[rawKey]), config)
+				} else if filter.Operator == "eq" && len(values[rawKey]) > 1 {
+					// The repeated params match any of their values, eg :
+					// "status=active&status=pending".
+					if i > 0 {
+						continue
+					}
+					filter.Operator = "in"
+					ok, err = filter.bindList(c, repeatedValues(rawKey, values[rawKey]), config)
+				} else {
+					ok, err = filter.bind(c, value, config)
+				}
+				if ok {
+					ok, err = filter.rewrite(config)
+				}
+				if err != nil {
+					errs = append(errs, &ParamError{Param: rawKey, Reason: err.Error()})
 				}
-				if len(expressions) == 1 {
-					db = db.Where(expressions[0])
-				} else if len(expressions) > 1 {
-					db = db.Where(clause.And(expressions...))
+				if ok {
+					filters = append(filters, filter)
 				}
 			}
 		}
 	}
+	defaults, defaultErrs := defaultFilters(c, modelType, sent, config)
+	filters, errs = append(filters, defaults...), append(errs, defaultErrs...)
+	return config.combineComposites(filters), errs
+}
 
-	return db
+// disabled reports whether param is listed in config.DisabledParams.
+func (config Config) disabled(param string) bool {
+	return contains(config.DisabledParams, param)
+}
+
+// withoutDisabled returns filters without the filters of the params listed
+// in config.DisabledParams.
+func (config Config) withoutDisabled(filters []Filter) []Filter {
+	if len(config.DisabledParams) == 0 {
+		return filters
+	}
+	kept := filters[:0]
+	for _, f := range filters {
+		if !config.disabled(f.Param) {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+// matchFilters returns the filters, without value, that key applies to.
+func matchFilters(key, separator string, modelType reflect.Type, config Config) []Filter {
+	key, operator, orNull, err := parseOperator(key, separator)
+	if err != nil {
+		return nil
+	}
+
+	var (
+		filters    []Filter
+		primaryKey string
+	)
+	for i := 0; i < modelType.NumField(); i++ {
+		field := modelType.Field(i)
+		if hasTagFlag(field, "json") {
+			for _, sub := range jsonFields(field) {
+				if sub.param == key {
+					filter := newFilter(sub.field, sub.param, getColumnNameForField(field), operator, orNull)
+					filter.jsonPath = sub.path
+					filters = append(filters, filter)
+				}
+			}
+			continue
+		}
+		param, column, ok := fieldParam(field)
+		if !ok || param != key {
+			continue
+		}
+		if primaryKey == "" {
+			primaryKey = config.primaryKeyField(modelType)
+		}
+		filter := newFilter(field, param, column, operator, orNull)
+		filter.primaryKey = field.Name == primaryKey
+		filters = append(filters, filter)
+	}
+	return filters
+}
+
+// newFilter returns the filter, without value, of field with the options of
+// its tag.
+func newFilter(field reflect.StructField, param, column, operator string, orNull bool) Filter {
+	filter := Filter{
+		Param:     param,
+		Column:    column,
+		Operator:  operator,
+		OrNull:    orNull,
+		fieldType: field.Type,
+	}
+	// The "any" sentinel matches everything, e.g. `filter:"filterable;any:any"`.
+	filter.any, filter.hasAny = tagOption(field, "any")
+	filter.currentUser = hasTagFlag(field, "current_user")
+	// Decimals stored as strings are numbers too, e.g. `filter:"filterable;decimal"`.
+	filter.decimal = hasTagFlag(field, "decimal")
+	filter.softDelete = isSoftDelete(field.Type)
+	// The arrays contain every value, or any of them with `filter:"filterable;array:overlaps"`.
+	if isArray(field.Type) {
+		filter.arrayMode = arrayContains
+		if mode, ok := tagOption(field, "array"); ok && mode == arrayOverlaps {
+			filter.arrayMode = arrayOverlaps
+		}
+	}
+	// Floats can be compared at a precision, e.g. `filter:"filterable;precision:1"`.
+	if precision, ok := tagOption(field, "precision"); ok && isFloat(field.Type) {
+		filter.precision, _ = strconv.Atoi(precision)
+		filter.hasPrecision = true
+	}
+	return filter
+}
+
+// bind sets the value of the filter. It returns false if the filter should
+// not be applied for this value, along with an error if the value is invalid.
+func (f *Filter) bind(c *gin.Context, value string, config Config) (bool, error) {
+	if config.NormalizeValues {
+		value = normalizeValue(value)
+	}
+	if f.hasAny && strings.EqualFold(value, f.any) {
+		return false, nil
+	}
+	if config.NullValues && f.bindNull(value) {
+		return true, nil
+	}
+	if f.currentUser && value == CurrentUserSentinel {
+		userID, ok := config.currentUserID(c)
+		if !ok {
+			return false, errors.New("no current user")
+		}
+		value = userID
+	}
+	if isNumeric(f.fieldType) || f.decimal {
+		value = config.normalizeNumber(value)
+	}
+	_, compared := comparisonSymbols[f.Operator]
+	if isNumeric(f.fieldType) && (compared || listOperators[f.Operator]) {
+		number, err := parseNumber(f.fieldType, value)
+		if err != nil {
+			return false, err
+		}
+		value = number
+	}
+	if isTime(f.fieldType) && (compared || listOperators[f.Operator]) {
+		if _, err := config.parseTime(value); err != nil {
+			return false, err
+		}
+	}
+	if f.hasPrecision && (compared || listOperators[f.Operator]) {
+		// The other operators, eg : "isnull", don't take a number.
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return false, errors.New("invalid number")
+		}
+		value = strconv.FormatFloat(number, 'f', f.precision, 64)
+	}
+	if f.primaryKey && config.CoercePrimaryKey {
+		key, err := coerceKey(f.fieldType, value)
+		if err != nil {
+			return false, errors.New("invalid primary key")
+		}
+		value = fmt.Sprint(key)
+	}
+	if f.Operator == hasFlag {
+		if err := f.validateFlag(value); err != nil {
+			return false, err
+		}
+	}
+	if f.Operator == "date" {
+		if err := validateDate(value); err != nil {
+			return false, err
+		}
+	}
+	if f.Operator == isNull {
+		if _, err := strconv.ParseBool(value); err != nil {
+			return false, errors.New("invalid boolean")
+		}
+	}
+	if f.Operator == isEmpty {
+		if err := f.validateEmpty(value); err != nil {
+			return false, err
+		}
+	}
+	if isLengthOperator(f.Operator) {
+		if err := f.validateLength(value); err != nil {
+			return false, err
+		}
+	}
+	if f.Operator == regexOperator {
+		if err := validateRegex(config, value); err != nil {
+			return false, err
+		}
+	}
+	f.Value = value
+	return true, nil
+}
+
+// rewrite passes the bound filter to config.RewriteFilter, which can change
+// its operator and value. It returns false if the filter is rejected, or if
+// the rewritten operator doesn't take that many values.
+func (f *Filter) rewrite(config Config) (bool, error) {
+	if config.RewriteFilter == nil {
+		return true, nil
+	}
+	operator, value, keep := config.RewriteFilter(f.Param, f.Operator, f.value())
+	if !keep {
+		return false, errors.New("rejected filter")
+	}
+	if _, ok := operators[operator]; !ok {
+		return false, errors.New("unknown operator " + operator)
+	}
+	f.Operator = operator
+	if listOperators[operator] {
+		f.Value, f.Values = "", splitList([]string{value})
+		if err := checkArity(operator, len(f.Values)); err != nil {
+			return false, err
+		}
+	} else {
+		f.Value, f.Values = value, nil
+	}
+	return true, nil
+}
+
+// bindList sets the values of a list filter, eg : "in". It fails if the
+// operator doesn't take that many values, eg : "between" takes 2. Each value
+// is bound like the value of a single filter, the invalid values being
+// dropped. A list left empty matches no rows, unless config.IgnoreEmptyLists
+// is set.
+func (f *Filter) bindList(c *gin.Context, values []string, config Config) (bool, error) {
+	if err := checkArity(f.Operator, len(values)); err != nil {
+		return false, err
+	}
+	var firstErr error
+	f.Values = make([]string, 0, len(values))
+	for _, value := range values {
+		element := *f
+		ok, err := element.bind(c, value, config)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if !ok {
+			// The "any" sentinel matches everything.
+			return false, nil
+		}
+		f.Values = append(f.Values, element.Value)
+	}
+	if len(f.Values) == 0 {
+		return !config.IgnoreEmptyLists, firstErr
+	}
+	if checkArity(f.Operator, len(f.Values)) != nil {
+		// An invalid value was dropped.
+		return false, firstErr
+	}
+	return true, firstErr
+}
+
+// value returns the value of the filter, the values of a list filter being
+// joined with commas.
+func (f Filter) value() string {
+	if listOperators[f.Operator] {
+		return strings.Join(f.Values, ",")
+	}
+	return f.Value
+}
+
+// parseOperator returns the param of key, the operator of its suffix or
+// separator and whether the suffix ends with "_or_null", eg : "age", "gte"
+// and false for "age__gte". It fails if the operator is not registered, eg :
+// "age__gte2", or missing, eg : "age__".
+func parseOperator(key, separator string) (string, string, bool, error) {
+	operator, orNull := separatorOperators[separator], false
+	if name, suffix, found := cutSuffix(key); found && separator == eq {
+		key = name
+		operator, orNull = strings.CutSuffix(suffix, orNullSuffix)
+		if operator == "" {
+			return key, "", false, errors.New("malformed operator")
+		}
+	}
+	if _, ok := operators[operator]; !ok {
+		return key, "", false, errors.New("unknown operator " + operator)
+	}
+	return key, operator, orNull, nil
+}
+
+// cutSuffix splits a "{param}__{operator}" key.
+func cutSuffix(key string) (string, string, bool) {
+	i := strings.LastIndex(key, suffixSeparator)
+	if i <= 0 {
+		return key, "", false
+	}
+	return key[:i], key[i+len(suffixSeparator):], true
+}
+
+func (f Filter) expression(db *gorm.DB, config Config) clause.Expression {
+	if f.expr != nil {
+		return f.expr
+	}
+	if f.having != nil {
+		// Applied by groupByHavingCounts.
+		return nil
+	}
+	var expression clause.Expression
+	if f.arrayMode != "" && (f.Operator == "eq" || f.Operator == "in") && db.Dialector.Name() == "postgres" {
+		expression = arrayExpression(db, f)
+	} else if symbol, ok := comparisonSymbols[f.Operator]; ok && f.hasPrecision {
+		expression = roundedComparison(db, f, symbol)
+	} else {
+		expression = operators[f.Operator](db, config, f)
+	}
+	if expression != nil && f.OrNull {
+		return clause.Or(expression, clause.Eq{Column: f.column(db), Value: nil})
+	}
+	return expression
+}
+
+func expressionByFilters(db *gorm.DB, filters []Filter, config Config) *gorm.DB {
+	if unscopes(filters) {
+		// The soft deleted rows are filtered by the request.
+		db = db.Unscoped()
+	}
+	joins := joinsRelations(filters)
+	expressions := make([]clause.Expression, 0, len(filters))
+	for _, filter := range filters {
+		if joins && filter.table == "" {
+			// The columns of the joined relations could be ambiguous.
+			filter.table = clause.CurrentTable
+		}
+		if expression := filter.expression(db, config); expression != nil {
+			expressions = append(expressions, expression)
+		}
+	}
+	if expression := joinAnd(expressions); expression != nil {
+		db = db.Where(expression)
+	}
+	db = joinManyToMany(db, filters)
+	return groupByHavingCounts(db, filters)
 }
 
 const (
@@ -137,6 +1093,9 @@ const (
 	lt  = "<"
 	neq = "!="
 	eq  = "="
+
+	suffixSeparator = "__"
+	orNullSuffix    = "_or_null"
 )
 
 var Separators = []string{
@@ -148,6 +1107,83 @@ var Separators = []string{
 	eq,
 }
 
+// separatorOperators maps the separators to the name of their operator, as
+// used in "{param}__{operator}={value}" keys.
+var separatorOperators = map[string]string{
+	gte: "gte",
+	gt:  "gt",
+	lte: "lte",
+	lt:  "lt",
+	neq: "neq",
+	eq:  "eq",
+}
+
+// OperatorBuilder builds the condition of a filter. db is only used to know
+// the dialect.
+type OperatorBuilder func(db *gorm.DB, config Config, f Filter) clause.Expression
+
+var operators = map[string]OperatorBuilder{
+	"eq": func(db *gorm.DB, config Config, f Filter) clause.Expression {
+		return clause.Eq{Column: f.column(db), Value: f.arg(config, f.Value)}
+	},
+	"neq": func(db *gorm.DB, config Config, f Filter) clause.Expression {
+		return clause.Neq{Column: f.column(db), Value: f.arg(config, f.Value)}
+	},
+	"gt": func(db *gorm.DB, config Config, f Filter) clause.Expression {
+		return clause.Gt{Column: f.column(db), Value: f.arg(config, f.Value)}
+	},
+	"gte": func(db *gorm.DB, config Config, f Filter) clause.Expression {
+		return clause.Gte{Column: f.column(db), Value: f.arg(config, f.Value)}
+	},
+	"lt": func(db *gorm.DB, config Config, f Filter) clause.Expression {
+		return clause.Lt{Column: f.column(db), Value: f.arg(config, f.Value)}
+	},
+	"lte": func(db *gorm.DB, config Config, f Filter) clause.Expression {
+		return clause.Lte{Column: f.column(db), Value: f.arg(config, f.Value)}
+	},
+	"in": func(db *gorm.DB, config Config, f Filter) clause.Expression {
+		if len(f.Values) == 0 {
+			return clause.Expr{SQL: "1=0"}
+		}
+		values := make([]interface{}, 0, len(f.Values))
+		for _, value := range f.Values {
+			values = append(values, f.arg(config, value))
+		}
+		if (config.ArrayBinding || config.StableStatements) && db.Dialector.Name() == "postgres" {
+			return clause.Expr{SQL: "? = ANY(?)", Vars: []interface{}{f.column(db), postgresArray(values)}}
+		}
+		return clause.IN{Column: f.column(db), Values: values}
+	},
+}
+
+var operatorNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+// RegisterOperator registers builder for the "{param}__{name}={value}"
+// filters, eg : a database specific operator. It is meant to be called from
+// an init function, and panics if the name is invalid or already registered.
+// Example:
+//
+//	filter.RegisterOperator("similar", func(db *gorm.DB, config filter.Config, f filter.Filter) clause.Expression {
+//		return clause.Expr{SQL: "? SIMILAR TO ?", Vars: []interface{}{clause.Column{Name: f.Column}, f.Value}}
+//	})
+func RegisterOperator(name string, builder OperatorBuilder) {
+	if !operatorNameRegexp.MatchString(name) {
+		panic("filter: invalid operator name " + name)
+	}
+	if builder == nil {
+		panic("filter: nil builder for operator " + name)
+	}
+	if _, ok := operators[name]; ok {
+		panic("filter: operator " + name + " already registered")
+	}
+	operators[name] = builder
+}
+
+// listOperators take every value of their param, eg : "id__in=1,2&id__in=3".
+var listOperators = map[string]bool{
+	"in": true,
+}
+
 func getSeparator(key, value string) (string, string, string) {
 	var arg string
 	if value == "" {
@@ -182,33 +1218,75 @@ func getSeparator(key, value string) (string, string, string) {
 //		return ret, err
 //	}
 func Paginate(c *gin.Context, db *gorm.DB, params QueryParams) *gorm.DB {
-	var count int64
-	db.Count(&count)
-	if params.Page == 0 {
-		params.Page = 1
+	count, err := countRows(db)
+	if err != nil {
+		db.AddError(err)
+		return db
 	}
+	return paginate(c, db, count, params)
+}
 
-	switch {
-	case params.Limit > 100:
-		params.Limit = 100
-	case params.Limit <= 0:
-		params.Limit = 10
-	}
+// countRows counts the rows matched by db. The count runs in a new session so
+// that the statement of db is left untouched, on the connection of db, which
+// is the transaction when db is one. The session keeps the scoping of db, eg :
+// Unscoped, but not its limit and offset, GORM dropping the order itself.
+func countRows(db *gorm.DB) (int64, error) {
+	var count int64
+	err := db.Session(&gorm.Session{}).Limit(-1).Offset(-1).Count(&count).Error
+	return count, err
+}
+
+// paginate limits db to the page of params, described in the headers of the
+// response of c if not nil.
+func paginate(c *gin.Context, db *gorm.DB, count int64, params QueryParams) *gorm.DB {
+	normalizePagination(&params)
 
 	maxPage := count / int64(params.Limit)
 	if count%int64(params.Limit) != 0 {
 		maxPage++
 	}
 
-	c.Header("X-Paginate-Items", strconv.FormatInt(count, 10))
-	c.Header("X-Paginate-Pages", strconv.FormatInt(maxPage, 10))
-	c.Header("X-Paginate-Current", strconv.Itoa(params.Page))
-	c.Header("X-Paginate-Limit", strconv.Itoa(params.Limit))
+	if c != nil {
+		c.Header("X-Paginate-Items", strconv.FormatInt(count, 10))
+		c.Header("X-Paginate-Pages", strconv.FormatInt(maxPage, 10))
+		c.Header("X-Paginate-Current", strconv.Itoa(params.Page))
+		c.Header("X-Paginate-Limit", strconv.Itoa(params.Limit))
+	}
 
 	offset := (params.Page - 1) * params.Limit
+	if params.Offset > 0 {
+		offset = params.Offset
+	}
 	return db.Offset(offset).Limit(params.Limit)
 }
 
+// exportAll limits the query to limit rows instead of paginating it. The
+// "X-Export-Truncated" header tells whether rows were left out, if c is not
+// nil.
+func exportAll(c *gin.Context, db *gorm.DB, count int64, limit int) *gorm.DB {
+	if c != nil {
+		c.Header("X-Paginate-Items", strconv.FormatInt(count, 10))
+		c.Header("X-Export-Truncated", strconv.FormatBool(count > int64(limit)))
+	}
+	return db.Limit(limit)
+}
+
+// CurrentUserSentinel is the value replaced by the id of the authenticated
+// user, eg : "owner_id=@me".
+const CurrentUserSentinel = "@me"
+
+// currentUserID returns the id of the authenticated user stored in c.
+func (config Config) currentUserID(c *gin.Context) (string, bool) {
+	if config.CurrentUserKey == "" || c == nil {
+		return "", false
+	}
+	userID, ok := c.Get(config.CurrentUserKey)
+	if !ok || userID == nil {
+		return "", false
+	}
+	return fmt.Sprint(userID), true
+}
+
 // Filter DB request with query parameters.
 // Note: Don't forget to initialize DB Model first, otherwise filter and search won't work
 // Example:
@@ -227,43 +1305,305 @@ func Paginate(c *gin.Context, db *gorm.DB, params QueryParams) *gorm.DB {
 //		// `param` defines custom column name for the query param
 //		FullName string `filter:"searchable"`
 //	}
+//
+// If config is 0 and the model implements ModelConfigurer, the config of the
+// model is used.
 func FilterByQuery(c *gin.Context, config int) func(db *gorm.DB) *gorm.DB {
+	if config == 0 {
+		return func(db *gorm.DB) *gorm.DB {
+			if model, ok := db.Statement.Model.(ModelConfigurer); ok {
+				return FilterByConfig(c, model.FilterConfig())(db)
+			}
+			return FilterByConfig(c, Config{})(db)
+		}
+	}
 	return FilterByQueryWithCustomDefault(c, config, QueryParams{})
 }
 
+// ModelConfigurer is implemented by the models defining their own config,
+// used by FilterByQuery when no config is given.
+type ModelConfigurer interface {
+	FilterConfig() Config
+}
+
 func FilterByQueryWithCustomDefault(c *gin.Context, config int, params QueryParams) func(db *gorm.DB) *gorm.DB {
+	return FilterByConfig(c, Config{Flags: config, Defaults: params})
+}
+
+// FilterByConfig is the same as FilterByQuery but takes a full Config.
+func FilterByConfig(c *gin.Context, config Config) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
-		setDefault(&params)
-		err := c.BindQuery(&params)
+		if config.ReadMethodsOnly && !readMethod(c) {
+			return db
+		}
+		return filterByValues(c, c.Request.URL.Query(), config)(db)
+	}
+}
+
+// filterByValues applies the params of values to db. c is the context of the
+// request, nil for the other transports, eg : FilterByMap.
+func filterByValues(c *gin.Context, values url.Values, config Config) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if config.OnBuild != nil {
+			start := time.Now()
+			defer func() {
+				config.OnBuild(c, time.Since(start))
+			}()
+		}
+		config.db = db
+		query, err := parseValues(c, values, db.Statement.Model, config)
 		if err != nil {
-			return nil
+			db.AddError(err)
+			return db
 		}
-		cleanParams := c.Request.URL.Query()
+		db = query.apply(c, db)
+		if config.OnQuery != nil {
+			config.OnQuery(c, query.summary(db))
+		}
+		return db
+	}
+}
+
+// readMethod reports whether the request of c is a GET or a HEAD request, an
+// empty method being GET.
+func readMethod(c *gin.Context) bool {
+	switch c.Request.Method {
+	case "", http.MethodGet, http.MethodHead:
+		return true
+	}
+	return false
+}
+
+// ConfigFunc computes the config of a request, eg : to disable the search
+// for the anonymous clients.
+type ConfigFunc func(c *gin.Context) Config
+
+// FilterByConfigFunc is the same as FilterByConfig but the config is computed
+// per request by configFunc.
+// Example:
+//
+//	db.Model(&UserModel{}).Scopes(filter.FilterByConfigFunc(c, func(c *gin.Context) filter.Config {
+//		if _, ok := c.Get("user"); !ok {
+//			return filter.Config{Flags: filter.FILTER | filter.PAGINATE}
+//		}
+//		return filter.Config{Flags: filter.ALL}
+//	})).Find(&users)
+func FilterByConfigFunc(c *gin.Context, configFunc ConfigFunc) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return FilterByConfig(c, configFunc(c))(db)
+	}
+}
+
+// ParseQuery binds the query params of the request and matches them against
+// the filterable fields of model. The params which can't be applied are
+// ignored, unless config.Strict is set. Without database, the relations are
+// resolved with the default naming strategy of GORM, while the scopes, eg :
+// FilterByConfig, use the naming strategy of their database.
+func ParseQuery(c *gin.Context, model interface{}, config Config) (*ParsedQuery, error) {
+	return parseValues(c, c.Request.URL.Query(), model, config)
+}
+
+// parseValues binds the params of values like ParseQuery. c is the context of
+// the request, nil for the other transports.
+func parseValues(c *gin.Context, values url.Values, model interface{}, config Config) (*ParsedQuery, error) {
+	config = config.withRequestTimezone(c)
+	if model == nil && len(config.Columns) > 0 {
+		model = columnsModel(config.Columns)
+	}
+	query := &ParsedQuery{Params: config.Defaults, Config: config}
+	setDefault(&query.Params)
+	if err := bindParams(values, &query.Params, config.FilterReservedParams); err != nil {
+		return nil, err
+	}
+	normalizePagination(&query.Params)
+	applySort(&query.Params, values, config.PreferOrderBy)
+	applyOffset(&query.Params, values, config.PreferPage)
+	if config.noOrder(values) {
+		query.Params.OrderBy = ""
+	}
+	if config.reverseOrder(values) {
+		reverseOrder(&query.Params)
+	}
 
-		model := db.Statement.Model
-		modelType := reflect.TypeOf(model)
-		if model != nil && modelType.Kind() == reflect.Ptr && modelType.Elem().Kind() == reflect.Struct {
-			if config&FILTER > 0 {
-				db = expressionByField(db, cleanParams, modelType.Elem())
+	modelType := reflect.TypeOf(model)
+	if model != nil && modelType.Kind() == reflect.Ptr && modelType.Elem().Kind() == reflect.Struct {
+		if config.Flags&FILTER > 0 {
+			var errs []error
+			query.Filters, errs = parseFilters(c, values, modelType.Elem(), config)
+			if len(config.PolymorphicOwners) > 0 {
+				polymorphic, params, polymorphicErrs := config.polymorphicFilters(c, values, modelType.Elem())
+				query.Filters = append(query.Filters, polymorphic...)
+				errs = append(withoutParams(errs, params), polymorphicErrs...)
+			}
+			if where := values.Get(whereParam); where != "" {
+				group, err := parseWhere(c, where, modelType.Elem(), config)
+				if err != nil {
+					errs = append(errs, &ParamError{Param: whereParam, Reason: err.Error()})
+				}
+				query.Where, query.where = group, where
+			}
+			if err := config.strictError(errs); err != nil {
+				return nil, err
 			}
 		}
-
-		stmt := &gorm.Statement{DB: db}
-		err = stmt.Parse(model)
-		if err != nil {
-			return nil
+		if err := query.validateOrderColumns(); err != nil {
+			return nil, err
 		}
-		table := stmt.Schema.Table
-		if config&PAGINATE > 0 {
-			db = Paginate(c, db, params)
+		if err := query.authorizeOrderColumns(c); err != nil {
+			return nil, err
 		}
+		if err := query.limitOrderColumns(); err != nil {
+			return nil, err
+		}
+		if err := query.validateDistinctOn(); err != nil {
+			return nil, err
+		}
+		if config.Flags&SEARCH > 0 && query.Params.Search != "" {
+			query.SearchColumns = searchColumns(modelType.Elem(), config)
+			if config.SearchPrimaryKey {
+				query.searchKey = config.primaryKeySearch(modelType.Elem(), query.Params.Search)
+			}
+		}
+	}
+	return query, nil
+}
 
-		if config&ORDER_BY > 0 {
-			db = orderBy(db, params, table)
+func (q *ParsedQuery) apply(c *gin.Context, db *gorm.DB) *gorm.DB {
+	db = expressionByFilters(db, q.Filters, q.Config)
+	if q.Where != nil {
+		if expression := q.Where.expression(db, q.Config); expression != nil {
+			db = db.Where(expression)
 		}
+	}
+	if !q.impliedSearch() {
+		db = expressionBySearch(db, q.Params.Search, q.SearchColumns, q.searchKey, joinsRelations(q.Filters), q.Config)
+	}
 
+	stmt := &gorm.Statement{DB: db}
+	var table string
+	if db.Statement.Model == nil && len(q.Config.Columns) > 0 {
+		// The view or the CTE has no schema, its columns are the ones of the
+		// config.
+		table = db.Statement.Table
+	} else if err := stmt.Parse(db.Statement.Model); err != nil {
+		db.AddError(err)
+		return db
+	} else {
+		table = stmt.Schema.Table
+	}
+	if !fetchesList(db) {
 		return db
 	}
+
+	distinctOn := q.Params.DistinctOn
+	if db.Dialector.Name() != "postgres" {
+		distinctOn = ""
+	}
+
+	distinct := distinctOn == "" && q.distinct(db)
+
+	if q.Config.Flags&PAGINATE > 0 {
+		// The count runs on the statement as filtered so far.
+		countDB := db.Session(&gorm.Session{}).Limit(-1)
+		if distinctOn != "" {
+			countDB = countDB.Distinct(distinctOn)
+		} else if distinct && stmt.Schema != nil && stmt.Schema.PrioritizedPrimaryField != nil {
+			countDB = countDB.Distinct(table + "." + stmt.Schema.PrioritizedPrimaryField.DBName)
+		}
+		db = db.Clauses(&listLimit{paginate: func(db *gorm.DB) *gorm.DB {
+			count, err := countRows(countDB)
+			if err != nil {
+				db.AddError(err)
+				return db
+			}
+			q.items = count
+			if first, last, ok := requestRange(c, q.Config.RangeUnit); ok {
+				db = paginateRange(c, db, count, first, last, q.Config)
+			} else if q.Params.All && q.Config.ExportLimit > 0 {
+				db = exportAll(c, db, count, q.Config.ExportLimit)
+			} else {
+				db = paginate(c, db, count, q.Params)
+			}
+			if q.Config.StableStatements {
+				db = bindLimit(db)
+			}
+			return db
+		}})
+	}
+
+	if distinctOn != "" && (q.Config.Flags&ORDER_BY == 0 || firstOrderColumn(q.Params) != distinctOn) {
+		// DISTINCT ON requires the order to start with the distinct column.
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: table + "." + distinctOn}})
+	}
+
+	if q.Config.Flags&ORDER_BY > 0 {
+		db = orderBy(db, q.orderParams(), table, q.Filters, q.Config)
+	}
+
+	var withCount []string
+	if len(q.Config.WithCount) > 0 && q.Params.WithCount != "" {
+		withCount = strings.Split(q.Params.WithCount, ",")
+	}
+	var (
+		columnsSQL  string
+		columnsVars []interface{}
+	)
+	if stmt.Schema != nil {
+		columnsSQL, columnsVars = relationCounts(stmt.Schema, withCount, q.Config.WithCount)
+	}
+	if distinct {
+		db = db.Distinct()
+	}
+	if distinctOn != "" || columnsSQL != "" || distinct {
+		sql := "?.*" + columnsSQL
+		vars := append([]interface{}{clause.Table{Name: table}}, columnsVars...)
+		if distinctOn != "" {
+			sql = "DISTINCT ON (?) " + sql
+			vars = append([]interface{}{clause.Column{Table: table, Name: distinctOn}}, vars...)
+		}
+		db = db.Select(sql, vars...)
+	}
+
+	return db
+}
+
+// fetchesList reports whether the statement the scope is applied to may fetch
+// a list of rows, so that pagination and order can be applied. Scopes are
+// executed before GORM knows the kind of statement, so it is guessed from the
+// destination: Find, Scan and Pluck fetch into a slice, Rows has no
+// destination yet, while Count, First or Update use a number, a struct or a
+// map. Delete and Update can use a slice too, eg : db.Delete(&users), so the
+// pagination waits for the SELECT to be built, see listLimit.
+func fetchesList(db *gorm.DB) bool {
+	if db.Statement.Dest == nil {
+		return true
+	}
+	destType := reflect.TypeOf(db.Statement.Dest)
+	for destType.Kind() == reflect.Ptr {
+		destType = destType.Elem()
+	}
+	return destType.Kind() == reflect.Slice || destType.Kind() == reflect.Array
+}
+
+// bindParams binds the query params to params, except the reserved params
+// listed in skip.
+func bindParams(values url.Values, params *QueryParams, skip []string) error {
+	if len(skip) > 0 {
+		kept := make(url.Values, len(values))
+		for key, keyValues := range values {
+			if !contains(skip, key) {
+				kept[key] = keyValues
+			}
+		}
+		values = kept
+	}
+	if err := binding.MapFormWithTag(params, values, "form"); err != nil {
+		return err
+	}
+	if binding.Validator == nil {
+		return nil
+	}
+	return binding.Validator.ValidateStruct(params)
 }
 
 func setDefault(p *QueryParams) {
@@ -284,3 +1624,16 @@ func setDefault(p *QueryParams) {
 		p.OrderDirection = "desc"
 	}
 }
+
+func normalizePagination(p *QueryParams) {
+	if p.Page <= 0 {
+		p.Page = 1
+	}
+
+	switch {
+	case p.Limit > 100:
+		p.Limit = 100
+	case p.Limit <= 0:
+		p.Limit = 10
+	}
+}
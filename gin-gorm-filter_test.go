@@ -7,10 +7,13 @@ package filter
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gin-gonic/gin"
@@ -64,6 +67,26 @@ func (s *TestSuite) TearDownTest() {
 	db.Close()
 }
 
+func newTestContext(rawQuery string) *gin.Context {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = &http.Request{
+		URL: &url.URL{
+			RawQuery: rawQuery,
+		},
+	}
+	return ctx
+}
+
+// publicFilters strips the unexported fields of filters for comparison.
+func publicFilters(filters []Filter) []Filter {
+	public := make([]Filter, 0, len(filters))
+	for _, f := range filters {
+		public = append(public, Filter{Param: f.Param, Column: f.Column, Operator: f.Operator, Value: f.Value, OrNull: f.OrNull})
+	}
+	return public
+}
+
 // TestFiltersBasic is a test suite for basic filters functionality.
 func (s *TestSuite) TestFiltersBasic() {
 	var users []User
@@ -115,7 +138,6 @@ func (s *TestSuite) TestFiltersNoFilterConfig() {
 	s.NoError(err)
 }
 
-/* // search function is disabled for now
 // TestFiltersSearchable is a test suite for searchable filters functionality.
 func (s *TestSuite) TestFiltersSearchable() {
 	var users []User
@@ -127,12 +149,12 @@ func (s *TestSuite) TestFiltersSearchable() {
 		},
 	}
 
-	s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE \("Username" LIKE \$1 OR "FullName" LIKE \$2\)`).
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE \("username" LIKE \$1 OR "full_name" LIKE \$2\)`).
 		WithArgs("%John%", "%John%").
 		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
-	err := s.db.Model(&User{}).Scopes(FilterByQuery(ctx, ALL)).Find(&users).Error
+	err := s.db.Model(&User{}).Scopes(FilterByQuery(ctx, SEARCH)).Find(&users).Error
 	s.NoError(err)
-}*/
+}
 
 // TestFiltersPaginateOnly is a test suite for pagination functionality.
 func (s *TestSuite) TestFiltersPaginateOnly() {
@@ -152,6 +174,63 @@ func (s *TestSuite) TestFiltersPaginateOnly() {
 	s.NoError(err)
 }
 
+// TestFiltersPaginateRows checks that the count doesn't alter the paginated query.
+func (s *TestSuite) TestFiltersPaginateRows() {
+	var users []User
+	ctx := newTestContext("page=1&limit=10")
+
+	s.mock.ExpectQuery(`^SELECT count\(\*\) FROM "users"`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" ORDER BY "users"\."created_at" DESC LIMIT 10$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username"}).AddRow(1, "a").AddRow(2, "b"))
+	err := s.db.Model(&User{}).Scopes(FilterByQuery(ctx, ALL)).Find(&users).Error
+	s.NoError(err)
+	s.Len(users, 2)
+}
+
+// TestFiltersTransaction checks that the count runs on the transaction.
+func (s *TestSuite) TestFiltersTransaction() {
+	var (
+		users []User
+		inTx  []bool
+	)
+	ctx := newTestContext("username=sampleUser&page=1&limit=10")
+	err := s.db.Callback().Query().Before("gorm:query").Register("test:in_tx", func(db *gorm.DB) {
+		_, ok := db.Statement.ConnPool.(gorm.TxCommitter)
+		inTx = append(inTx, ok)
+	})
+	s.NoError(err)
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery(`^SELECT count\(\*\) FROM "users" WHERE "username" = \$1$`).
+		WithArgs("sampleUser").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE "username" = \$1 ORDER BY "users"\."created_at" DESC LIMIT 10$`).
+		WithArgs("sampleUser").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username"}).AddRow(1, "sampleUser"))
+	s.mock.ExpectCommit()
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		return tx.Model(&User{}).Scopes(FilterByQuery(ctx, ALL)).Find(&users).Error
+	})
+	s.NoError(err)
+	s.Equal([]bool{true, true}, inTx)
+	s.NoError(s.mock.ExpectationsWereMet())
+}
+
+// TestFiltersCountError checks that a failed count fails the query.
+func (s *TestSuite) TestFiltersCountError() {
+	var users []User
+	ctx := newTestContext("page=1")
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery(`^SELECT count\(\*\) FROM "users"`).WillReturnError(errors.New("current transaction is aborted"))
+	s.mock.ExpectRollback()
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		return tx.Model(&User{}).Scopes(FilterByQuery(ctx, ALL)).Find(&users).Error
+	})
+	s.EqualError(err, "current transaction is aborted")
+	s.NoError(s.mock.ExpectationsWereMet())
+}
+
 // TestFiltersOrderBy is a test suite for order by functionality.
 func (s *TestSuite) TestFiltersOrderBy() {
 	var users []User
@@ -169,6 +248,220 @@ func (s *TestSuite) TestFiltersOrderBy() {
 	s.NoError(err)
 }
 
+// TestFiltersUpdate checks that only the filters apply to an update.
+func (s *TestSuite) TestFiltersUpdate() {
+	ctx := newTestContext("username=sampleUser&page=2&order_by=email")
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(`^UPDATE "users" SET "email"=\$1 WHERE "username" = \$2$`).
+		WithArgs("new@example.com", "sampleUser").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+	err := s.db.Model(&User{}).Scopes(FilterByQuery(ctx, ALL)).Update("email", "new@example.com").Error
+	s.NoError(err)
+	s.NoError(s.mock.ExpectationsWereMet())
+}
+
+// TestFiltersDelete checks that only the filters apply to a delete.
+func (s *TestSuite) TestFiltersDelete() {
+	ctx := newTestContext("username=sampleUser&limit=5")
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(`^DELETE FROM "users" WHERE "username" = \$1$`).
+		WithArgs("sampleUser").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+	err := s.db.Model(&User{}).Scopes(FilterByQuery(ctx, ALL)).Delete(&User{}).Error
+	s.NoError(err)
+	s.NoError(s.mock.ExpectationsWereMet())
+}
+
+// TestFiltersDeleteSlice checks that a delete into a slice is neither counted
+// nor paginated.
+func (s *TestSuite) TestFiltersDeleteSlice() {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = &http.Request{URL: &url.URL{RawQuery: "username=sampleUser&limit=5"}}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(`^DELETE FROM "users" WHERE "username" = \$1$`).
+		WithArgs("sampleUser").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+	var users []User
+	err := s.db.Model(&User{}).Scopes(FilterByQuery(ctx, ALL)).Delete(&users).Error
+	s.NoError(err)
+	s.NoError(s.mock.ExpectationsWereMet())
+	s.Empty(w.Header().Get("X-Paginate-Items"))
+}
+
+// TestFiltersOnBuild checks that the build duration is reported.
+func (s *TestSuite) TestFiltersOnBuild() {
+	var (
+		users   []User
+		elapsed time.Duration
+		calls   int
+	)
+	ctx := newTestContext("username=sampleUser")
+	config := Config{Flags: FILTER, OnBuild: func(c *gin.Context, d time.Duration) {
+		s.Same(ctx, c)
+		elapsed = d
+		calls++
+	}}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE "username" = \$1$`).
+		WithArgs("sampleUser").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err := s.db.Model(&User{}).Scopes(FilterByConfig(ctx, config)).Find(&users).Error
+	s.NoError(err)
+	s.Equal(1, calls)
+	s.Greater(elapsed, time.Duration(0))
+}
+
+type Article struct {
+	Id    int64
+	Title string `filter:"filterable"`
+}
+
+func (Article) FilterConfig() Config {
+	return Config{Flags: FILTER | ORDER_BY, Defaults: QueryParams{OrderBy: "title", OrderDirection: "asc"}}
+}
+
+// TestFiltersModelConfig checks that the config of the model is used without explicit config.
+func (s *TestSuite) TestFiltersModelConfig() {
+	var articles []Article
+	ctx := newTestContext("title=news&page=2")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "articles" WHERE "title" = \$1 ORDER BY "articles"\."title"$`).
+		WithArgs("news").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}))
+	err := s.db.Model(&Article{}).Scopes(FilterByQuery(ctx, 0)).Find(&articles).Error
+	s.NoError(err)
+}
+
+type Account struct {
+	Id       int64
+	Verified bool `filter:"filterable;any:any"`
+}
+
+// TestFiltersAnySentinel checks the true/false/any states of a boolean filter.
+func (s *TestSuite) TestFiltersAnySentinel() {
+	for _, value := range []string{"true", "false"} {
+		var accounts []Account
+		ctx := newTestContext("verified=" + value)
+		s.mock.ExpectQuery(`^SELECT \* FROM "accounts" WHERE "verified" = \$1$`).
+			WithArgs(value).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "verified"}))
+		err := s.db.Model(&Account{}).Scopes(FilterByQuery(ctx, FILTER)).Find(&accounts).Error
+		s.NoError(err)
+	}
+
+	var accounts []Account
+	ctx := newTestContext("verified=ANY")
+	s.mock.ExpectQuery(`^SELECT \* FROM "accounts"$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "verified"}))
+	err := s.db.Model(&Account{}).Scopes(FilterByQuery(ctx, FILTER)).Find(&accounts).Error
+	s.NoError(err)
+}
+
 func TestRunSuite(t *testing.T) {
 	suite.Run(t, new(TestSuite))
 }
+
+// TestFiltersRewrite checks that the filters can be rejected or rewritten.
+func (s *TestSuite) TestFiltersRewrite() {
+	var products []Product
+	ctx := newTestContext("name__like=phone&price__gt=-5&where=" + url.QueryEscape("name__like=tv OR price__gt=-1"))
+	config := Config{Flags: FILTER, RewriteFilter: func(param, operator, value string) (string, string, bool) {
+		if operator == "like" {
+			return "", "", false
+		}
+		if operator == "gt" && strings.HasPrefix(value, "-") {
+			return "gte", "0", true
+		}
+		return operator, value, true
+	}}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "products" WHERE "price" >= \$1$`).
+		WithArgs("0").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "price"}))
+	err := s.db.Model(&Product{}).Scopes(FilterByConfig(ctx, config)).Find(&products).Error
+	s.NoError(err)
+
+	config.Strict = true
+	_, err = ParseQuery(ctx, &Product{}, config)
+	s.ErrorContains(err, "rejected filter")
+}
+
+// TestFiltersConfigFunc checks that the config computed for the request
+// disables the search of the anonymous clients.
+func (s *TestSuite) TestFiltersConfigFunc() {
+	var users []User
+	configFunc := func(c *gin.Context) Config {
+		if _, ok := c.Get("user"); !ok {
+			return Config{Flags: FILTER}
+		}
+		return Config{Flags: FILTER | SEARCH}
+	}
+
+	ctx := newTestContext("search=John&email=john@example.com")
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE "email" = \$1$`).
+		WithArgs("john@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err := s.db.Model(&User{}).Scopes(FilterByConfigFunc(ctx, configFunc)).Find(&users).Error
+	s.NoError(err)
+
+	ctx = newTestContext("search=John&email=john@example.com")
+	ctx.Set("user", "admin")
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE "email" = \$1 AND \("username" LIKE \$2 OR "full_name" LIKE \$3\)$`).
+		WithArgs("john@example.com", "%John%", "%John%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err = s.db.Model(&User{}).Scopes(FilterByConfigFunc(ctx, configFunc)).Find(&users).Error
+	s.NoError(err)
+}
+
+// TestFiltersReadMethodsOnly checks that the query params of a POST request
+// are ignored when the scope is limited to the read methods.
+func (s *TestSuite) TestFiltersReadMethodsOnly() {
+	var users []User
+	config := Config{Flags: ALL, ReadMethodsOnly: true}
+	ctx := newTestContext("username=john&page=2")
+	ctx.Request.Method = http.MethodPost
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "users"$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err := s.db.Model(&User{}).Scopes(FilterByConfig(ctx, config)).Find(&users).Error
+	s.NoError(err)
+
+	ctx = newTestContext("username=john")
+	ctx.Request.Method = http.MethodGet
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE "username" = \$1$`).
+		WithArgs("john").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err = s.db.Model(&User{}).Scopes(FilterByConfig(ctx, Config{Flags: FILTER, ReadMethodsOnly: true})).Find(&users).Error
+	s.NoError(err)
+}
+
+// TestFiltersDisabledParams checks that a disabled param is ignored although
+// its field is filterable, and reported as unknown in strict mode.
+func (s *TestSuite) TestFiltersDisabledParams() {
+	var users []User
+	config := Config{Flags: FILTER, DisabledParams: []string{"email"}}
+	ctx := newTestContext("email=john@example.com&username=john")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE "username" = \$1$`).
+		WithArgs("john").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err := s.db.Model(&User{}).Scopes(FilterByConfig(ctx, config)).Find(&users).Error
+	s.NoError(err)
+
+	config.Strict = true
+	for query, reason := range map[string]string{
+		"email=john@example.com":                             "filter: email: unknown filter",
+		"email__neq=john@example.com":                        "filter: email__neq: unknown filter",
+		"where=" + url.QueryEscape("email=john@example.com"): "filter: where: unknown filter email in where expression",
+	} {
+		_, err = ParseQuery(newTestContext(query), &User{}, config)
+		s.EqualError(err, reason, query)
+	}
+}
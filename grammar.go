@@ -0,0 +1,267 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// The "where" param accepts conditions grouped with AND, OR and parentheses:
+//
+//	expression := term { "OR" term }
+//	term       := factor { "AND" factor }
+//	factor     := "(" expression ")" | condition
+//	condition  := param operator value
+//
+// where operator is one of the separators (eg : ">=") and value is either a
+// bare word or a quoted string, eg :
+//
+//	(status=active AND age__gt=18) OR role="super admin"
+//
+// Conditions are matched against the filterable fields like the other filters.
+const (
+	whereParam    = "where"
+	maxWhereDepth = 8
+	maxWhereNodes = 32
+)
+
+// FilterGroup is a node of a "where" expression: either a single filter or
+// children joined with AND, or with OR if Or is set.
+type FilterGroup struct {
+	Filter   *Filter
+	Or       bool
+	Children []FilterGroup
+}
+
+func (g FilterGroup) expression(db *gorm.DB, config Config) clause.Expression {
+	if g.Filter != nil {
+		return g.Filter.expression(db, config)
+	}
+	expressions := make([]clause.Expression, 0, len(g.Children))
+	for _, child := range g.Children {
+		if expression := child.expression(db, config); expression != nil {
+			expressions = append(expressions, expression)
+		}
+	}
+	if g.Or {
+		return joinOr(expressions)
+	}
+	return joinAnd(expressions)
+}
+
+// String returns the expression with explicit parentheses.
+func (g FilterGroup) String() string {
+	if g.Filter != nil {
+		return g.Filter.Column + " " + g.Filter.Operator + " " + g.Filter.value()
+	}
+	separator := " AND "
+	if g.Or {
+		separator = " OR "
+	}
+	parts := make([]string, 0, len(g.Children))
+	for _, child := range g.Children {
+		parts = append(parts, child.String())
+	}
+	return "(" + strings.Join(parts, separator) + ")"
+}
+
+// joinAnd joins expressions with AND. Unlike clause.And, a single expression
+// is returned as is.
+func joinAnd(expressions []clause.Expression) clause.Expression {
+	switch len(expressions) {
+	case 0:
+		return nil
+	case 1:
+		return expressions[0]
+	}
+	return clause.And(expressions...)
+}
+
+// joinOr joins expressions with OR. A single expression is returned as is:
+// GORM would join a single clause.Or with the previous conditions using OR.
+func joinOr(expressions []clause.Expression) clause.Expression {
+	switch len(expressions) {
+	case 0:
+		return nil
+	case 1:
+		return expressions[0]
+	}
+	return clause.Or(expressions...)
+}
+
+var errWhereSyntax = errors.New("invalid where expression")
+
+type whereParser struct {
+	c         *gin.Context
+	input     string
+	pos       int
+	nodes     int
+	modelType reflect.Type
+	config    Config
+}
+
+// parseWhere parses a "where" expression against the filterable fields of
+// modelType.
+func parseWhere(c *gin.Context, input string, modelType reflect.Type, config Config) (*FilterGroup, error) {
+	p := &whereParser{c: c, input: input, modelType: modelType, config: config}
+	group, err := p.expression(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpaces(); p.pos != len(p.input) {
+		return nil, errWhereSyntax
+	}
+	return group, nil
+}
+
+func (p *whereParser) expression(depth int) (*FilterGroup, error) {
+	return p.join(depth, "OR", p.term)
+}
+
+func (p *whereParser) term(depth int) (*FilterGroup, error) {
+	return p.join(depth, "AND", p.factor)
+}
+
+// join parses operands separated by the keyword.
+func (p *whereParser) join(depth int, keyword string, operand func(int) (*FilterGroup, error)) (*FilterGroup, error) {
+	first, err := operand(depth)
+	if err != nil {
+		return nil, err
+	}
+	group := &FilterGroup{Or: keyword == "OR", Children: []FilterGroup{*first}}
+	for p.keyword(keyword) {
+		next, err := operand(depth)
+		if err != nil {
+			return nil, err
+		}
+		group.Children = append(group.Children, *next)
+	}
+	if len(group.Children) == 1 {
+		return first, nil
+	}
+	return group, nil
+}
+
+func (p *whereParser) factor(depth int) (*FilterGroup, error) {
+	p.skipSpaces()
+	if p.pos < len(p.input) && p.input[p.pos] == '(' {
+		if depth >= maxWhereDepth {
+			return nil, errors.New("where expression too deep")
+		}
+		p.pos++
+		group, err := p.expression(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if p.skipSpaces(); p.pos >= len(p.input) || p.input[p.pos] != ')' {
+			return nil, errWhereSyntax
+		}
+		p.pos++
+		return group, nil
+	}
+	return p.condition()
+}
+
+func (p *whereParser) condition() (*FilterGroup, error) {
+	p.nodes++
+	if p.nodes > maxWhereNodes {
+		return nil, errors.New("too many conditions in where expression")
+	}
+	start := p.pos
+	for p.pos < len(p.input) && isParamChar(p.input[p.pos]) {
+		p.pos++
+	}
+	key := p.input[start:p.pos]
+	separator := ""
+	for _, s := range Separators {
+		if strings.HasPrefix(p.input[p.pos:], s) {
+			separator = s
+			break
+		}
+	}
+	if key == "" || separator == "" {
+		return nil, errWhereSyntax
+	}
+	p.pos += len(separator)
+	value, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+
+	matched := p.config.withoutDisabled(matchFilters(key, separator, p.modelType, p.config))
+	if len(matched) != 1 {
+		return nil, errors.New("unknown filter " + key + " in where expression")
+	}
+	filter := matched[0]
+	if !p.config.authorized(p.c, filter.Param, FILTER) {
+		return nil, errors.New("unauthorized filter " + key + " in where expression")
+	}
+	var ok bool
+	if listOperators[filter.Operator] {
+		ok, err = filter.bindList(p.c, splitList([]string{value}), p.config)
+	} else {
+		ok, err = filter.bind(p.c, value, p.config)
+	}
+	if err == nil && ok {
+		ok, err = filter.rewrite(p.config)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		// The filter matches everything, eg : the "any" sentinel.
+		return &FilterGroup{}, nil
+	}
+	return &FilterGroup{Filter: &filter}, nil
+}
+
+func (p *whereParser) value() (string, error) {
+	if p.pos < len(p.input) && (p.input[p.pos] == '"' || p.input[p.pos] == '\'') {
+		quote := p.input[p.pos]
+		end := strings.IndexByte(p.input[p.pos+1:], quote)
+		if end < 0 {
+			return "", errWhereSyntax
+		}
+		value := p.input[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+		return value, nil
+	}
+	start := p.pos
+	for p.pos < len(p.input) && !strings.ContainsRune(" ()", rune(p.input[p.pos])) {
+		p.pos++
+	}
+	return p.input[start:p.pos], nil
+}
+
+// keyword consumes the keyword, case insensitive, if it comes next.
+func (p *whereParser) keyword(keyword string) bool {
+	p.skipSpaces()
+	end := p.pos + len(keyword)
+	if end > len(p.input) || !strings.EqualFold(p.input[p.pos:end], keyword) {
+		return false
+	}
+	if end < len(p.input) && p.input[end] != ' ' && p.input[end] != '(' {
+		return false
+	}
+	p.pos = end
+	return true
+}
+
+func (p *whereParser) skipSpaces() {
+	for p.pos < len(p.input) && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func isParamChar(c byte) bool {
+	return c == '_' || c == '.' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"net/url"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type Member struct {
+	Id     int64
+	Status string `filter:"filterable"`
+	Age    int    `filter:"filterable"`
+	Role   string `filter:"filterable"`
+	Secret string
+}
+
+// TestFiltersWhereNested checks that a nested expression keeps its parentheses.
+func (s *TestSuite) TestFiltersWhereNested() {
+	var members []Member
+	ctx := newTestContext("status=active&where=" + url.QueryEscape(`(status=active AND age__gt=18) or role="super admin"`))
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "members" WHERE "status" = \$1 AND \(\("status" = \$2 AND "age" > \$3\) OR "role" = \$4\)$`).
+		WithArgs("active", "active", "18", "super admin").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "age", "role"}))
+	err := s.db.Model(&Member{}).Scopes(FilterByQuery(ctx, FILTER)).Find(&members).Error
+	s.NoError(err)
+}
+
+// TestFiltersWhereInvalid checks that invalid expressions are rejected.
+func (s *TestSuite) TestFiltersWhereInvalid() {
+	for _, where := range []string{
+		`status=active AND secret=x`,
+		`(status=active OR role=admin`,
+		`status=active OR`,
+		`status active`,
+		`status=active; DROP TABLE members`,
+	} {
+		ctx := newTestContext("where=" + url.QueryEscape(where))
+		_, err := ParseQuery(ctx, &Member{}, Config{Flags: FILTER, Strict: true})
+		s.Error(err, where)
+
+		query, err := ParseQuery(ctx, &Member{}, Config{Flags: FILTER})
+		s.NoError(err)
+		s.Nil(query.Where, where)
+	}
+}
+
+// TestFiltersWhereList checks that the values of the list filters of an
+// expression are split.
+func (s *TestSuite) TestFiltersWhereList() {
+	var members []Member
+	ctx := newTestContext("where=" + url.QueryEscape("status__in=a,b OR role=x"))
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "members" WHERE \("status" IN \(\$1,\$2\) OR "role" = \$3\)$`).
+		WithArgs("a", "b", "x").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "age", "role"}))
+	err := s.db.Model(&Member{}).Scopes(FilterByQuery(ctx, FILTER)).Find(&members).Error
+	s.NoError(err)
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "members" WHERE "age" IN \(\$1,\$2\)$`).
+		WithArgs("1", "2").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "age", "role"}))
+	err = s.db.Model(&Member{}).Scopes(FilterByConfig(newTestContext("where=age__in=1,2"), Config{Flags: FILTER, Strict: true})).Find(&members).Error
+	s.NoError(err)
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "members" WHERE "status" BETWEEN \$1 AND \$2$`).
+		WithArgs("a", "b").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "age", "role"}))
+	err = s.db.Model(&Member{}).Scopes(FilterByQuery(newTestContext("where=status__between=a,b"), FILTER)).Find(&members).Error
+	s.NoError(err)
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"regexp"
+	"strings"
+)
+
+// defaultMaxIdentifierLength is the maximum length of the identifiers when
+// Config.MaxIdentifierLength is not set, as truncated by Postgres.
+const defaultMaxIdentifierLength = 63
+
+// identifierPattern matches the identifiers which can be sent by the clients.
+// They are quoted anyway, but the other characters are never legitimate.
+var identifierPattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// validIdentifier reports whether name is an identifier that can be used in
+// a statement.
+func (config Config) validIdentifier(name string) bool {
+	maxLength := config.MaxIdentifierLength
+	if maxLength <= 0 {
+		maxLength = defaultMaxIdentifierLength
+	}
+	return len(name) <= maxLength && identifierPattern.MatchString(name)
+}
+
+// validateOrderColumns drops the order columns which are not valid
+// identifiers, eg : "name;drop". It reports them in strict mode.
+func (q *ParsedQuery) validateOrderColumns() error {
+	if q.Config.Flags&ORDER_BY == 0 {
+		return nil
+	}
+	var (
+		names []string
+		errs  []error
+	)
+	for _, name := range strings.Split(q.Params.OrderBy, ",") {
+		column := strings.TrimPrefix(strings.TrimSpace(name), "-")
+		column = strings.TrimSuffix(column, inOrderSuffix)
+		if column != "" && !q.Config.validIdentifier(column) {
+			errs = append(errs, &ParamError{Param: "order_by", Reason: "invalid column"})
+			continue
+		}
+		names = append(names, name)
+	}
+	q.Params.OrderBy = strings.Join(names, ",")
+	return q.Config.strictError(errs)
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// inOrderSuffix orders a column by the position of its values in its "in"
+// filter, eg : "order_by=id:in_order" with "id__in=3,1,2".
+const inOrderSuffix = ":in_order"
+
+// inOrderExpression returns the order of column by the position of its values
+// in its "in" filter, eg : array_position($1, "users"."id") on Postgres. It
+// returns nil if the column has no "in" filter.
+func inOrderExpression(db *gorm.DB, column orderColumn, table string, filters []Filter, config Config) clause.Expression {
+	var values []interface{}
+	for _, f := range filters {
+		if f.Operator == "in" && f.Column == column.name && len(f.jsonPath) == 0 && len(f.Values) > 0 {
+			for _, value := range f.Values {
+				values = append(values, f.arg(config, value))
+			}
+			break
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	direction := ""
+	if column.desc {
+		direction = " DESC"
+	}
+	name := clause.Column{Table: table, Name: column.name}
+	switch db.Dialector.Name() {
+	case "postgres":
+		return clause.Expr{SQL: "array_position(?, ?)" + direction, Vars: []interface{}{postgresArray(values), name}}
+	case "mysql":
+		sql := "FIELD(?" + strings.Repeat(", ?", len(values)) + ")" + direction
+		return clause.Expr{SQL: sql, Vars: append([]interface{}{name}, values...)}
+	}
+	var sql strings.Builder
+	vars := make([]interface{}, 0, 2*len(values))
+	sql.WriteString("CASE")
+	for i, value := range values {
+		sql.WriteString(" WHEN ? = ? THEN ?")
+		vars = append(vars, name, value, i)
+	}
+	sql.WriteString(" END" + direction)
+	return clause.Expr{SQL: sql.String(), Vars: vars}
+}
+
+// orderColumnExpression returns the expression of an order column.
+func orderColumnExpression(column clause.OrderByColumn) clause.Expression {
+	if column.Desc {
+		return clause.Expr{SQL: "? DESC", Vars: []interface{}{column.Column}}
+	}
+	return clause.Expr{SQL: "?", Vars: []interface{}{column.Column}}
+}
+
+// orderByExpressions orders db by expressions, after its current order
+// columns. The expressions have vars, which the columns of clause.OrderBy
+// can't hold, and its expression replaces the columns when built.
+func orderByExpressions(db *gorm.DB, expressions []clause.Expression) *gorm.DB {
+	if current, ok := db.Statement.Clauses["ORDER BY"].Expression.(clause.OrderBy); ok {
+		previous := make([]clause.Expression, 0, len(current.Columns)+len(expressions))
+		for _, column := range current.Columns {
+			previous = append(previous, orderColumnExpression(column))
+		}
+		expressions = append(previous, expressions...)
+	}
+	return db.Clauses(clause.OrderBy{Expression: clause.CommaExpression{Exprs: expressions}})
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// jsonField is a filterable subfield of a JSON column, eg : "address.city"
+// for the City field of the Address struct stored in the column.
+type jsonField struct {
+	param string
+	path  []string
+	field reflect.StructField
+}
+
+// jsonFields returns the subfields tagged `filterable` of the struct stored in
+// the JSON column of field, tagged `filter:"json"`. The params are the JSON
+// keys of the subfields joined with dots.
+func jsonFields(field reflect.StructField) []jsonField {
+	var fields []jsonField
+	var walk func(t reflect.Type, path []string)
+	walk = func(t reflect.Type, path []string) {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct || t == reflect.TypeOf(time.Time{}) {
+			return
+		}
+		for i := 0; i < t.NumField(); i++ {
+			sub := t.Field(i)
+			key := jsonKey(sub)
+			if key == "" {
+				continue
+			}
+			subPath := append(append([]string{}, path...), key)
+			if hasTagFlag(sub, "filterable") {
+				fields = append(fields, jsonField{param: strings.Join(subPath, "."), path: subPath, field: sub})
+				continue
+			}
+			walk(sub.Type, subPath)
+		}
+	}
+	walk(field.Type, nil)
+	return fields
+}
+
+// jsonKey returns the key of field in its JSON document, or "" if the field
+// is not encoded.
+func jsonKey(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
+
+// column returns the filtered column, or the path of the filtered subfield of
+// a JSON column, eg : "data"->'address'->>'city' on Postgres. The numbers are
+// compared as numerics on Postgres.
+func (f Filter) column(db *gorm.DB) clause.Column {
+	if len(f.jsonPath) == 0 {
+		return clause.Column{Table: f.table, Name: f.Column}
+	}
+	column := db.Statement.Quote(f.Column)
+	switch db.Dialector.Name() {
+	case "mysql", "sqlite":
+		return clause.Column{Name: column + "->>" + jsonString("$."+strings.Join(f.jsonPath, ".")), Raw: true}
+	}
+	last := len(f.jsonPath) - 1
+	for _, key := range f.jsonPath[:last] {
+		column += "->" + jsonString(key)
+	}
+	column += "->>" + jsonString(f.jsonPath[last])
+	if isNumeric(f.fieldType) {
+		column = "(" + column + ")::numeric"
+	}
+	return clause.Column{Name: column, Raw: true}
+}
+
+// jsonString quotes a JSON key for SQL. The key comes from the struct tags,
+// never from the request.
+func jsonString(key string) string {
+	return "'" + strings.ReplaceAll(key, "'", "''") + "'"
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type ShopAddress struct {
+	City    string `json:"city" filter:"filterable"`
+	Country string `json:"country"`
+}
+
+type ShopData struct {
+	Address ShopAddress `json:"address"`
+	Rating  float64     `json:"rating" filter:"filterable"`
+}
+
+type Shop struct {
+	Id   int64
+	Name string   `filter:"filterable"`
+	Data ShopData `gorm:"type:jsonb;serializer:json" filter:"json"`
+}
+
+// TestFiltersJSONPath checks the path of the filtered JSON subfields.
+func (s *TestSuite) TestFiltersJSONPath() {
+	var shops []Shop
+	ctx := newTestContext("address.city=Paris&rating__gte=4&address.country=France")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "shops" WHERE "data"->'address'->>'city' = \$1 AND \("data"->>'rating'\)::numeric >= \$2$`).
+		WithArgs("Paris", "4").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "data"}))
+	err := s.db.Model(&Shop{}).Scopes(FilterByQuery(ctx, FILTER)).Find(&shops).Error
+	s.NoError(err)
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// primaryKeyField returns the name of the primary key field of modelType, as
+// resolved by gorm, or "" if it has none.
+func (config Config) primaryKeyField(modelType reflect.Type) string {
+	modelSchema, err := config.parseSchema(modelType)
+	if err != nil || modelSchema.PrioritizedPrimaryField == nil {
+		return ""
+	}
+	return modelSchema.PrioritizedPrimaryField.Name
+}
+
+// coerceKey converts value to the kind of the primary key type t: the
+// integers are parsed, the other keys, eg : uuid strings, are kept as is.
+func coerceKey(t reflect.Type, value string) (interface{}, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(value, 10, t.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(value, 10, t.Bits())
+	}
+	return value, nil
+}
+
+// arg returns value, converted to the type of the primary key if the filter
+// is on the primary key and config.CoercePrimaryKey is set, or to a time.Time
+// if the filter is on a time field.
+func (f Filter) arg(config Config, value string) interface{} {
+	if isTime(f.fieldType) {
+		if t, err := config.parseTime(value); err == nil {
+			return t
+		}
+		return value
+	}
+	if !f.primaryKey || !config.CoercePrimaryKey {
+		return value
+	}
+	if key, err := coerceKey(f.fieldType, value); err == nil {
+		return key
+	}
+	return value
+}
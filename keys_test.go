@@ -0,0 +1,45 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type Coupon struct {
+	Code     string `gorm:"primaryKey" filter:"filterable"`
+	Discount int    `filter:"filterable"`
+}
+
+// TestFiltersCoerceIntPrimaryKey checks that the ids are sent as integers and the invalid ones dropped.
+func (s *TestSuite) TestFiltersCoerceIntPrimaryKey() {
+	var albums []Album
+	ctx := newTestContext("id__in=3,abc,007&title=Blue")
+	config := Config{Flags: FILTER, CoercePrimaryKey: true}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "albums" WHERE "id" IN \(\$1,\$2\) AND "title" = \$3$`).
+		WithArgs(int64(3), int64(7), "Blue").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}))
+	err := s.db.Model(&Album{}).Scopes(FilterByConfig(ctx, config)).Find(&albums).Error
+	s.NoError(err)
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "albums"$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}))
+	err = s.db.Model(&Album{}).Scopes(FilterByConfig(newTestContext("id=abc"), config)).Find(&albums).Error
+	s.NoError(err)
+}
+
+// TestFiltersCoerceStringPrimaryKey checks that a string key is kept as a string.
+func (s *TestSuite) TestFiltersCoerceStringPrimaryKey() {
+	var coupons []Coupon
+	ctx := newTestContext("code=42&discount=10")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "coupons" WHERE "code" = \$1 AND "discount" = \$2$`).
+		WithArgs("42", "10").
+		WillReturnRows(sqlmock.NewRows([]string{"code", "discount"}))
+	err := s.db.Model(&Coupon{}).Scopes(FilterByConfig(ctx, Config{Flags: FILTER, CoercePrimaryKey: true})).Find(&coupons).Error
+	s.NoError(err)
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// lengthPrefix prefixes the comparison operators comparing the length of a
+// string or array column, eg : "name__len_gt=10".
+const lengthPrefix = "len_"
+
+func init() {
+	for name, symbol := range comparisonSymbols {
+		symbol := symbol
+		operators[lengthPrefix+name] = func(db *gorm.DB, _ Config, f Filter) clause.Expression {
+			length, _ := strconv.Atoi(f.Value)
+			return clause.Expr{
+				SQL:  lengthFunction(db, f.fieldType) + "(?) " + symbol + " ?",
+				Vars: []interface{}{f.column(db), length},
+			}
+		}
+	}
+}
+
+// lengthFunction returns the SQL function returning the length of a column of
+// type t, eg : cardinality for the arrays on Postgres.
+func lengthFunction(db *gorm.DB, t reflect.Type) string {
+	array := isArray(t)
+	switch db.Dialector.Name() {
+	case "postgres":
+		if array {
+			return "cardinality"
+		}
+	case "mysql":
+		if array {
+			return "JSON_LENGTH"
+		}
+		return "CHAR_LENGTH"
+	case "sqlite":
+		if array {
+			return "json_array_length"
+		}
+	}
+	return "LENGTH"
+}
+
+// validateLength checks that the filter is on a string or array field and
+// value is a length, as expected by the length operators.
+func (f Filter) validateLength(value string) error {
+	if !isArray(f.fieldType) && !isString(f.fieldType) {
+		return errors.New("not a string or array field")
+	}
+	if length, err := strconv.Atoi(value); err != nil || length < 0 {
+		return errors.New("invalid length")
+	}
+	return nil
+}
+
+// isLengthOperator reports whether operator compares a length.
+func isLengthOperator(operator string) bool {
+	return strings.HasPrefix(operator, lengthPrefix)
+}
+
+// isArray reports whether t, or the type it points to, is a slice or an array,
+// bytes excepted.
+func isArray(t reflect.Type) bool {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return false
+	}
+	return t.Elem().Kind() != reflect.Uint8
+}
+
+// isString reports whether t, or the type it points to, is a string.
+func isString(t reflect.Type) bool {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t != nil && t.Kind() == reflect.String
+}
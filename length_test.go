@@ -0,0 +1,45 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type Story struct {
+	Id    int64
+	Title string   `filter:"filterable"`
+	Tags  []string `gorm:"type:text[]" filter:"filterable"`
+	Views int64    `filter:"filterable"`
+}
+
+// TestFiltersLength checks the length of the strings and the cardinality of
+// the arrays on Postgres.
+func (s *TestSuite) TestFiltersLength() {
+	var stories []Story
+	ctx := newTestContext("title__len_gt=10&tags__len_lte=3")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "stories" WHERE cardinality\("tags"\) <= \$1 AND LENGTH\("title"\) > \$2$`).
+		WithArgs(3, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "views"}))
+	err := s.db.Model(&Story{}).Scopes(FilterByQuery(ctx, FILTER)).Find(&stories).Error
+	s.NoError(err)
+}
+
+// TestFiltersLengthInvalid checks that the lengths must be positive integers
+// on string or array fields.
+func (s *TestSuite) TestFiltersLengthInvalid() {
+	config := Config{Flags: FILTER, Strict: true}
+
+	_, err := ParseQuery(newTestContext("title__len_gt=ten"), &Story{}, config)
+	s.EqualError(err, "filter: title__len_gt: invalid length")
+
+	_, err = ParseQuery(newTestContext("title__len_eq=-1"), &Story{}, config)
+	s.EqualError(err, "filter: title__len_eq: invalid length")
+
+	_, err = ParseQuery(newTestContext("views__len_gt=2"), &Story{}, config)
+	s.EqualError(err, "filter: views__len_gt: not a string or array field")
+}
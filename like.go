@@ -0,0 +1,33 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern returns the LIKE pattern matching the strings containing
+// value. The wildcards of value are escaped.
+func containsPattern(value string) string {
+	return "%" + likeEscaper.Replace(value) + "%"
+}
+
+func init() {
+	operators["like"] = func(db *gorm.DB, _ Config, f Filter) clause.Expression {
+		return clause.Like{Column: f.column(db), Value: containsPattern(f.Value)}
+	}
+	operators["notlike"] = func(db *gorm.DB, _ Config, f Filter) clause.Expression {
+		return clause.Expr{
+			SQL:  "? NOT LIKE ?",
+			Vars: []interface{}{f.column(db), containsPattern(f.Value)},
+		}
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// TestFiltersNotLike checks the negated pattern and the escaping of wildcards.
+func (s *TestSuite) TestFiltersNotLike() {
+	var users []User
+	ctx := newTestContext("username__notlike=te%25st_&email__like=example")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE "email" LIKE \$1 AND "username" NOT LIKE \$2$`).
+		WithArgs("%example%", `%te\%st\_%`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err := s.db.Model(&User{}).Scopes(FilterByQuery(ctx, FILTER)).Find(&users).Error
+	s.NoError(err)
+}
+
+// TestFiltersNotLikeNotFilterable checks that the operator requires a filterable field.
+func (s *TestSuite) TestFiltersNotLikeNotFilterable() {
+	var users []User
+	ctx := newTestContext("password__notlike=secret")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "users"$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err := s.db.Model(&User{}).Scopes(FilterByQuery(ctx, FILTER)).Find(&users).Error
+	s.NoError(err)
+}
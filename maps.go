@@ -0,0 +1,35 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"gorm.io/gorm"
+)
+
+// FilterByMap is the same as FilterByConfig for the transports which don't
+// go through gin, eg : a gRPC gateway passing the filters as metadata. values
+// are the query params, eg : {"username": {"john"}, "page": {"2"}}. Without
+// request, the hooks of config, eg : AuthorizeField, are called with a nil
+// context, the context keys, eg : CurrentUserKey, and the Range header are
+// ignored, and no pagination header is written: the count is available with
+// ParseValues and Meta.
+// Example:
+//
+//	db.Model(&UserModel{}).Scopes(filter.FilterByMap(md, filter.Config{Flags: filter.ALL})).Find(&users)
+func FilterByMap(values map[string][]string, config Config) func(db *gorm.DB) *gorm.DB {
+	return filterByValues(nil, values, config)
+}
+
+// ParseValues is the same as ParseQuery for the params of values, eg : the
+// metadata of a gRPC request. The query is applied with a nil context.
+// Example:
+//
+//	query, err := filter.ParseValues(md, &UserModel{}, config)
+//	err = db.Model(&UserModel{}).Scopes(query.Scope(nil)).Find(&users).Error
+//	meta := query.Meta()
+func ParseValues(values map[string][]string, model interface{}, config Config) (*ParsedQuery, error) {
+	return parseValues(nil, values, model, config)
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// TestFilterByMap checks that the filters and the pagination of a map are
+// applied like the ones of a request.
+func (s *TestSuite) TestFilterByMap() {
+	var users []User
+	values := map[string][]string{"username": {"john"}, "page": {"2"}, "limit": {"10"}}
+
+	s.mock.ExpectQuery(`^SELECT count\(\*\) FROM "users" WHERE "username" = \$1$`).
+		WithArgs("john").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(15))
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE "username" = \$1 ORDER BY "users"\."created_at" DESC LIMIT 10 OFFSET 10$`).
+		WithArgs("john").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username"}))
+	err := s.db.Model(&User{}).Scopes(FilterByMap(values, Config{Flags: ALL})).Find(&users).Error
+	s.NoError(err)
+}
+
+// TestParseValues checks that the query parsed from a map is applied without
+// request and counts its rows for the meta.
+func (s *TestSuite) TestParseValues() {
+	var users []User
+	query, err := ParseValues(map[string][]string{"username": {"john"}, "limit": {"10"}}, &User{}, Config{Flags: FILTER | PAGINATE})
+	s.Require().NoError(err)
+
+	s.mock.ExpectQuery(`^SELECT count\(\*\) FROM "users" WHERE "username" = \$1$`).
+		WithArgs("john").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(15))
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE "username" = \$1 LIMIT 10$`).
+		WithArgs("john").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username"}))
+	err = s.db.Model(&User{}).Scopes(query.Scope(nil)).Find(&users).Error
+	s.NoError(err)
+	s.Equal(Meta{Page: 1, Limit: 10, Items: 15, Pages: 2}, query.Meta())
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import "reflect"
+
+// Merge returns base with the fields set in override, eg : a preset shared by
+// several endpoints and the options of one of them. The zero fields of
+// override, such as false or nil, inherit from base, and so do the zero
+// fields of its Defaults.
+// Example:
+//
+//	var listConfig = filter.Config{Flags: filter.ALL, Strict: true, MaxOrderColumns: 2}
+//
+//	db.Model(&UserModel{}).Scopes(filter.FilterByConfig(c, filter.Merge(listConfig, filter.Config{SearchFields: []string{"username"}}))).Find(&users)
+func Merge(base, override Config) Config {
+	merged := base
+	mergeFields(reflect.ValueOf(&merged).Elem(), reflect.ValueOf(override))
+	return merged
+}
+
+// mergeFields sets the non-zero fields of src to dst, field by field for the
+// nested structs.
+func mergeFields(dst, src reflect.Value) {
+	for i := 0; i < src.NumField(); i++ {
+		field := src.Field(i)
+		if field.Kind() == reflect.Struct {
+			mergeFields(dst.Field(i), field)
+			continue
+		}
+		if !field.IsZero() {
+			dst.Field(i).Set(field)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+// TestMergeConfig checks that the fields set in the override win and that the
+// others are inherited from the base.
+func (s *TestSuite) TestMergeConfig() {
+	base := Config{
+		Flags:           ALL,
+		Strict:          true,
+		MaxOrderColumns: 2,
+		SearchFields:    []string{"username"},
+		Defaults:        QueryParams{Limit: 50, OrderBy: "id"},
+	}
+	override := Config{
+		Flags:        FILTER | PAGINATE,
+		SearchFields: []string{"email"},
+		Defaults:     QueryParams{OrderBy: "email"},
+		Timezone:     "Europe/Paris",
+	}
+
+	merged := Merge(base, override)
+	s.Equal(FILTER|PAGINATE, merged.Flags)
+	s.Equal([]string{"email"}, merged.SearchFields)
+	s.Equal("Europe/Paris", merged.Timezone)
+	s.Equal("email", merged.Defaults.OrderBy)
+	s.True(merged.Strict)
+	s.Equal(2, merged.MaxOrderColumns)
+	s.Equal(50, merged.Defaults.Limit)
+	s.Equal([]string{"username"}, base.SearchFields)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Meta describes a paginated response, eg : the "meta" of a JSON envelope.
+type Meta struct {
+	Page  int   `json:"page"`
+	Limit int   `json:"limit"`
+	Items int64 `json:"items"`
+	Pages int64 `json:"pages"`
+	// SearchHasMore tells that the search matches more rows than the ones
+	// returned up to this page.
+	SearchHasMore bool `json:"search_has_more,omitempty"`
+	// AppliedFilters are the filters of the query params, if
+	// Config.MetaAppliedFilters is set.
+	AppliedFilters []AppliedFilter `json:"applied_filters,omitempty"`
+}
+
+// AppliedFilter is a filter applied to a response.
+type AppliedFilter struct {
+	Field    string `json:"field"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+}
+
+// Scope applies the parsed query to db, like FilterByConfig.
+// Example:
+//
+//	query, err := filter.ParseQuery(c, &UserModel{}, config)
+//	err = db.Model(&UserModel{}).Scopes(query.Scope(c)).Find(&users).Error
+//	c.JSON(http.StatusOK, gin.H{"data": users, "meta": query.Meta()})
+func (q *ParsedQuery) Scope(c *gin.Context) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return q.apply(c, db)
+	}
+}
+
+// Meta returns the pagination of the query, once applied by Scope, and its
+// normalized filters if Config.MetaAppliedFilters is set.
+func (q *ParsedQuery) Meta() Meta {
+	meta := Meta{Page: q.Params.Page, Limit: q.Params.Limit, Items: q.items}
+	if q.Params.Limit > 0 {
+		meta.Pages = (q.items + int64(q.Params.Limit) - 1) / int64(q.Params.Limit)
+	}
+	if q.Params.Search != "" && (len(q.SearchColumns) > 0 || q.searchKey != nil) {
+		end := int64(q.Params.Page * q.Params.Limit)
+		if q.Params.Offset > 0 {
+			end = int64(q.Params.Offset + q.Params.Limit)
+		}
+		meta.SearchHasMore = q.items > end
+	}
+	if q.Config.MetaAppliedFilters {
+		meta.AppliedFilters = make([]AppliedFilter, 0, len(q.Filters))
+		for _, f := range q.Filters {
+			meta.AppliedFilters = append(meta.AppliedFilters, AppliedFilter{Field: f.Param, Operator: f.Operator, Value: f.value()})
+		}
+	}
+	return meta
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// TestMetaAppliedFilters checks the meta of a paginated request with several filters.
+func (s *TestSuite) TestMetaAppliedFilters() {
+	var products []Product
+	ctx := newTestContext("price__gte=10&name=phone&page=2&limit=5")
+
+	query, err := ParseQuery(ctx, &Product{}, Config{Flags: FILTER | PAGINATE, MetaAppliedFilters: true})
+	s.NoError(err)
+	s.mock.ExpectQuery(`^SELECT count\(\*\) FROM "products"`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+	s.mock.ExpectQuery(`^SELECT \* FROM "products" WHERE "name" = \$1 AND "price" >= \$2 LIMIT 5 OFFSET 5$`).
+		WithArgs("phone", "10").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "price"}))
+	err = s.db.Model(&Product{}).Scopes(query.Scope(ctx)).Find(&products).Error
+	s.NoError(err)
+
+	s.Equal(Meta{
+		Page:  2,
+		Limit: 5,
+		Items: 11,
+		Pages: 3,
+		AppliedFilters: []AppliedFilter{
+			{Field: "name", Operator: "eq", Value: "phone"},
+			{Field: "price", Operator: "gte", Value: "10"},
+		},
+	}, query.Meta())
+}
+
+// TestMetaSearchHasMore checks that the meta tells whether the search matches
+// more rows than the ones returned up to the current page.
+func (s *TestSuite) TestMetaSearchHasMore() {
+	var users []User
+	for count, hasMore := range map[int]bool{11: true, 10: false} {
+		ctx := newTestContext("search=john&page=2&limit=5")
+		query, err := ParseQuery(ctx, &User{}, Config{Flags: SEARCH | PAGINATE})
+		s.NoError(err)
+		s.mock.ExpectQuery(`^SELECT count\(\*\) FROM "users" WHERE \("username" LIKE \$1 OR "full_name" LIKE \$2\)$`).
+			WithArgs("%john%", "%john%").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+		s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE \("username" LIKE \$1 OR "full_name" LIKE \$2\) LIMIT 5 OFFSET 5$`).
+			WithArgs("%john%", "%john%").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "username"}))
+		err = s.db.Model(&User{}).Scopes(query.Scope(ctx)).Find(&users).Error
+		s.NoError(err)
+
+		s.Equal(hasMore, query.Meta().SearchHasMore, count)
+	}
+}
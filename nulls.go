@@ -0,0 +1,99 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// nullValue is the value matching the NULL columns with the null-safe
+// operators, eg : "status__iseq=null".
+const nullValue = "null"
+
+const isEmpty = "empty"
+
+func init() {
+	operators["iseq"] = nullSafeEqual
+	// The empty strings are not NULL, eg : "bio__empty=true" matches '' but
+	// not NULL, which "bio__isnull=true" matches.
+	operators[isEmpty] = func(db *gorm.DB, _ Config, f Filter) clause.Expression {
+		if empty, _ := strconv.ParseBool(f.Value); empty {
+			return clause.Expr{SQL: "? = ''", Vars: []interface{}{f.column(db)}}
+		}
+		return clause.Expr{SQL: "? <> ''", Vars: []interface{}{f.column(db)}}
+	}
+}
+
+// validateEmpty checks that the filter is on a string field and value is a
+// boolean, as expected by the "empty" operator.
+func (f Filter) validateEmpty(value string) error {
+	if !isString(f.fieldType) {
+		return errors.New("not a string field")
+	}
+	if _, err := strconv.ParseBool(value); err != nil {
+		return errors.New("invalid boolean")
+	}
+	return nil
+}
+
+// nullSafeEqual compares the column to the value treating NULL as a value,
+// eg : "status__iseq=null" matches the NULL statuses.
+func nullSafeEqual(db *gorm.DB, _ Config, f Filter) clause.Expression {
+	var value interface{} = f.Value
+	if strings.EqualFold(f.Value, nullValue) {
+		value = nil
+	}
+	column := f.column(db)
+	switch db.Dialector.Name() {
+	case "mysql":
+		return clause.Expr{SQL: "? <=> ?", Vars: []interface{}{column, value}}
+	case "sqlite":
+		return clause.Expr{SQL: "? IS ?", Vars: []interface{}{column, value}}
+	}
+	return clause.Expr{SQL: "? IS NOT DISTINCT FROM ?", Vars: []interface{}{column, value}}
+}
+
+// bindNull turns the equality filters of a nullable field with the "null"
+// value into the "isnull" operator, eg : "manager_id=null" filters on IS NULL
+// and "manager_id__neq=null" on IS NOT NULL. It reports whether the filter was
+// turned.
+func (f *Filter) bindNull(value string) bool {
+	if !strings.EqualFold(value, nullValue) || !isNullable(f.fieldType) || len(f.jsonPath) > 0 {
+		return false
+	}
+	switch f.Operator {
+	case "eq":
+		f.Value = "true"
+	case "neq":
+		f.Value = "false"
+	default:
+		return false
+	}
+	f.Operator = isNull
+	return true
+}
+
+// isNullable reports whether t holds NULL values, ie : a pointer or a
+// sql.Null type with a Valid field.
+func isNullable(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+	if t.Kind() == reflect.Ptr {
+		return true
+	}
+	if t.Kind() == reflect.Struct {
+		valid, ok := t.FieldByName("Valid")
+		return ok && valid.Type.Kind() == reflect.Bool
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// TestFiltersNullSafeEqual checks the null-safe equality with a value and with NULL.
+func (s *TestSuite) TestFiltersNullSafeEqual() {
+	for value, arg := range map[string]driver.Value{"active": "active", "null": nil} {
+		var tickets []Ticket
+		ctx := newTestContext("state__iseq=" + value)
+
+		s.mock.ExpectQuery(`^SELECT \* FROM "tickets" WHERE "state" IS NOT DISTINCT FROM \$1$`).
+			WithArgs(arg).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "state"}))
+		err := s.db.Model(&Ticket{}).Scopes(FilterByQuery(ctx, FILTER)).Find(&tickets).Error
+		s.NoError(err, value)
+	}
+}
+
+type Profile struct {
+	Id  int64
+	Bio *string `filter:"filterable"`
+	Age int64   `filter:"filterable"`
+}
+
+// TestFiltersEmptyString checks that the empty strings and the NULL values
+// are told apart.
+func (s *TestSuite) TestFiltersEmptyString() {
+	var profiles []Profile
+	for query, condition := range map[string]string{
+		"bio__empty=true":   `"bio" = ''`,
+		"bio__empty=false":  `"bio" <> ''`,
+		"bio__isnull=true":  `"bio" IS NULL`,
+		"bio__isnull=false": `"bio" IS NOT NULL`,
+	} {
+		s.mock.ExpectQuery(`^SELECT \* FROM "profiles" WHERE ` + regexp.QuoteMeta(condition) + `$`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "bio", "age"}))
+		err := s.db.Model(&Profile{}).Scopes(FilterByQuery(newTestContext(query), FILTER)).Find(&profiles).Error
+		s.NoError(err, query)
+	}
+
+	_, err := ParseQuery(newTestContext("age__empty=true"), &Profile{}, Config{Flags: FILTER, Strict: true})
+	s.EqualError(err, "filter: age__empty: not a string field")
+}
+
+type Task struct {
+	Id         int64
+	Title      string         `filter:"filterable"`
+	AssigneeId *int64         `filter:"filterable"`
+	Note       sql.NullString `filter:"filterable"`
+}
+
+// TestFiltersNullValues checks that "null" matches the NULL columns of the
+// nullable fields, and is a literal for the other fields.
+func (s *TestSuite) TestFiltersNullValues() {
+	var tasks []Task
+	config := Config{Flags: FILTER, NullValues: true}
+	for query, condition := range map[string]string{
+		"assignee_id=null":      `"assignee_id" IS NULL`,
+		"assignee_id__neq=NULL": `"assignee_id" IS NOT NULL`,
+		"note=null":             `"note" IS NULL`,
+	} {
+		s.mock.ExpectQuery(`^SELECT \* FROM "tasks" WHERE ` + regexp.QuoteMeta(condition) + `$`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "assignee_id", "note"}))
+		err := s.db.Model(&Task{}).Scopes(FilterByConfig(newTestContext(query), config)).Find(&tasks).Error
+		s.NoError(err, query)
+	}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "tasks" WHERE "title" = \$1$`).
+		WithArgs("null").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "assignee_id", "note"}))
+	err := s.db.Model(&Task{}).Scopes(FilterByConfig(newTestContext("title=null"), config)).Find(&tasks).Error
+	s.NoError(err)
+}
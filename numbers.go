@@ -0,0 +1,128 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// comparisonSymbols are the SQL symbols of the comparison operators.
+var comparisonSymbols = map[string]string{
+	"eq":  "=",
+	"neq": "<>",
+	"gt":  ">",
+	"gte": ">=",
+	"lt":  "<",
+	"lte": "<=",
+}
+
+// isNumeric reports whether t, or the type it points to, is a number.
+func isNumeric(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// normalizeNumber converts a number written with the separators of config to
+// the "1234.56" form expected by the databases.
+func (config Config) normalizeNumber(value string) string {
+	if config.StripCurrency {
+		value = stripCurrency(value)
+		if config.DecimalSeparator == "" {
+			return strings.ReplaceAll(value, ",", "")
+		}
+	}
+	if config.DecimalSeparator == "" {
+		return value
+	}
+	if config.GroupingSeparator != "" {
+		value = strings.ReplaceAll(value, config.GroupingSeparator, "")
+	}
+	return strings.ReplaceAll(value, config.DecimalSeparator, ".")
+}
+
+// stripCurrency removes the currency symbols and the spaces of an amount, eg :
+// "1 234,50 €" becomes "1234,50".
+func stripCurrency(value string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Sc, r) || unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, value)
+}
+
+// parseNumber checks that value is a number of the kind of t, or the type it
+// points to, eg : in the range of an int8. The floats can be written in
+// scientific notation, eg : "1e6", which is returned in decimal notation.
+func parseNumber(t reflect.Type, value string) (string, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var err error
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		_, err = strconv.ParseInt(value, 10, t.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		_, err = strconv.ParseUint(value, 10, t.Bits())
+	case reflect.Float32, reflect.Float64:
+		var number float64
+		number, err = strconv.ParseFloat(value, t.Bits())
+		if err == nil && (math.IsNaN(number) || math.IsInf(number, 0)) {
+			return "", errors.New("invalid number")
+		}
+		if err == nil && strings.ContainsAny(value, "eExX") {
+			value = strconv.FormatFloat(number, 'f', -1, t.Bits())
+		}
+	}
+	if errors.Is(err, strconv.ErrRange) {
+		return "", errors.New("number out of range")
+	}
+	if err != nil {
+		return "", errors.New("invalid number")
+	}
+	return value, nil
+}
+
+// isFloat reports whether t, or the type it points to, is a float.
+func isFloat(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64
+}
+
+// roundedComparison compares a float column rounded at the precision of the
+// filter, the value being already rounded, eg : ROUND("rating", 1) = $1.
+// Postgres only rounds numerics at a precision.
+func roundedComparison(db *gorm.DB, f Filter, symbol string) clause.Expression {
+	round := "ROUND(?, " + strconv.Itoa(f.precision) + ")"
+	if db.Dialector.Name() == "postgres" {
+		round = "ROUND(?::numeric, " + strconv.Itoa(f.precision) + ")"
+	}
+	return clause.Expr{
+		SQL:  round + " " + symbol + " ?",
+		Vars: []interface{}{f.column(db), f.Value},
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type Product struct {
+	Id    int64
+	Name  string  `filter:"filterable"`
+	Price float64 `filter:"filterable"`
+}
+
+// TestFiltersLocaleNumber checks that a European formatted number is normalized.
+func (s *TestSuite) TestFiltersLocaleNumber() {
+	var products []Product
+	ctx := newTestContext("price__gte=1.234,56&name=1.234,56")
+	config := Config{Flags: FILTER, DecimalSeparator: ",", GroupingSeparator: "."}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "products" WHERE "name" = \$1 AND "price" >= \$2$`).
+		WithArgs("1.234,56", "1234.56").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "price"}))
+	err := s.db.Model(&Product{}).Scopes(FilterByConfig(ctx, config)).Find(&products).Error
+	s.NoError(err)
+}
+
+type Review struct {
+	Id     int64
+	Rating float64 `filter:"filterable;precision:1"`
+}
+
+// TestFiltersFloatPrecision checks that both sides are rounded at the field precision.
+func (s *TestSuite) TestFiltersFloatPrecision() {
+	var reviews []Review
+	ctx := newTestContext("rating__eq=4.46&rating__lt=5")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "reviews" WHERE ROUND\("rating"::numeric, 1\) = \$1 AND ROUND\("rating"::numeric, 1\) < \$2$`).
+		WithArgs("4.5", "5.0").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "rating"}))
+	err := s.db.Model(&Review{}).Scopes(FilterByQuery(ctx, FILTER)).Find(&reviews).Error
+	s.NoError(err)
+}
+
+// TestFiltersFloatPrecisionIsNull checks that the values of the operators
+// which don't take a number are not rounded.
+func (s *TestSuite) TestFiltersFloatPrecisionIsNull() {
+	var reviews []Review
+	ctx := newTestContext("rating__isnull=true")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "reviews" WHERE "rating" IS NULL$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "rating"}))
+	err := s.db.Model(&Review{}).Scopes(FilterByConfig(ctx, Config{Flags: FILTER, Strict: true})).Find(&reviews).Error
+	s.NoError(err)
+}
+
+type Payment struct {
+	Id     int64
+	Amount float64 `filter:"filterable"`
+	Total  string  `filter:"filterable;decimal"`
+}
+
+// TestFiltersCurrencyAmount checks that the currency symbols and the grouping
+// separators are removed from the amounts.
+func (s *TestSuite) TestFiltersCurrencyAmount() {
+	var payments []Payment
+	ctx := newTestContext("amount__gte=$1,234.50&total__lt=%C2%A31,000,000")
+	config := Config{Flags: FILTER, StripCurrency: true}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "payments" WHERE "amount" >= \$1 AND "total" < \$2$`).
+		WithArgs("1234.50", "1000000").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "amount", "total"}))
+	err := s.db.Model(&Payment{}).Scopes(FilterByConfig(ctx, config)).Find(&payments).Error
+	s.NoError(err)
+}
+
+// TestFiltersCurrencyLocaleAmount checks that a European amount is normalized
+// with the separators of the config.
+func (s *TestSuite) TestFiltersCurrencyLocaleAmount() {
+	var payments []Payment
+	ctx := newTestContext("amount=1.234,50%20%E2%82%AC")
+	config := Config{Flags: FILTER, StripCurrency: true, DecimalSeparator: ",", GroupingSeparator: "."}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "payments" WHERE "amount" = \$1$`).
+		WithArgs("1234.50").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "amount", "total"}))
+	err := s.db.Model(&Payment{}).Scopes(FilterByConfig(ctx, config)).Find(&payments).Error
+	s.NoError(err)
+}
+
+type Measure struct {
+	Id    int64
+	Value float64 `filter:"filterable"`
+	Count int8    `filter:"filterable"`
+}
+
+// TestFiltersScientificNotation checks that the floats can be sent in
+// scientific notation.
+func (s *TestSuite) TestFiltersScientificNotation() {
+	var measures []Measure
+	ctx := newTestContext("value__gte=1e6&value__lt=2.5E-3")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "measures" WHERE "value" >= \$1 AND "value" < \$2$`).
+		WithArgs("1000000", "0.0025").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "value", "count"}))
+	err := s.db.Model(&Measure{}).Scopes(FilterByQuery(ctx, FILTER)).Find(&measures).Error
+	s.NoError(err)
+}
+
+// TestFiltersNumberOutOfRange checks that the values out of the range of the
+// field, or which are not numbers, are skipped.
+func (s *TestSuite) TestFiltersNumberOutOfRange() {
+	var measures []Measure
+	ctx := newTestContext("count=300&count__in=1,-129,abc&value=NaN")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "measures" WHERE "count" = \$1$`).
+		WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "value", "count"}))
+	err := s.db.Model(&Measure{}).Scopes(FilterByQuery(ctx, FILTER)).Find(&measures).Error
+	s.NoError(err)
+
+	_, err = ParseQuery(newTestContext("count__gt=300"), &Measure{}, Config{Flags: FILTER, Strict: true})
+	s.EqualError(err, "filter: count__gt: number out of range")
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// QuerySummary describes the capabilities applied by a scope to a query, eg :
+// to count the usage of the filters by endpoint.
+type QuerySummary struct {
+	// Filters is the number of filters of the query params.
+	Filters int
+	// Fields are the params of these filters, sorted and without duplicates.
+	Fields    []string
+	Searched  bool
+	Paginated bool
+	Ordered   bool
+}
+
+// summary returns the summary of q applied to db.
+func (q *ParsedQuery) summary(db *gorm.DB) QuerySummary {
+	summary := QuerySummary{Filters: len(q.Filters)}
+	seen := map[string]bool{}
+	for _, f := range q.Filters {
+		if !seen[f.Param] {
+			seen[f.Param] = true
+			summary.Fields = append(summary.Fields, f.Param)
+		}
+	}
+	sort.Strings(summary.Fields)
+	summary.Searched = q.Params.Search != "" && (len(q.SearchColumns) > 0 || q.searchKey != nil) && !q.impliedSearch()
+	if fetchesList(db) {
+		summary.Paginated = q.Config.Flags&PAGINATE > 0
+		summary.Ordered = q.Config.Flags&ORDER_BY > 0
+	}
+	return summary
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+)
+
+// TestFiltersOnQuery checks that the summary of the applied capabilities is
+// reported once the query is built.
+func (s *TestSuite) TestFiltersOnQuery() {
+	var (
+		users     []User
+		summaries []QuerySummary
+	)
+	ctx := newTestContext("username=john&email__neq=a@b.c&search=jo&limit=10")
+	config := Config{Flags: FILTER | SEARCH | PAGINATE, OnQuery: func(c *gin.Context, summary QuerySummary) {
+		s.Same(ctx, c)
+		summaries = append(summaries, summary)
+	}}
+
+	s.mock.ExpectQuery(`^SELECT count\(\*\) FROM "users"`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectQuery(`^SELECT \* FROM "users"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username"}))
+	err := s.db.Model(&User{}).Scopes(FilterByConfig(ctx, config)).Find(&users).Error
+	s.NoError(err)
+	s.Equal([]QuerySummary{{
+		Filters:   2,
+		Fields:    []string{"email", "username"},
+		Searched:  true,
+		Paginated: true,
+	}}, summaries)
+}
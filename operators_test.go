@@ -0,0 +1,39 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func init() {
+	RegisterOperator("similar", func(_ *gorm.DB, _ Config, f Filter) clause.Expression {
+		return clause.Expr{SQL: "? SIMILAR TO ?", Vars: []interface{}{clause.Column{Name: f.Column}, f.Value}}
+	})
+}
+
+// TestRegisterOperator checks that a registered operator builds the condition of its filters.
+func (s *TestSuite) TestRegisterOperator() {
+	var users []User
+	ctx := newTestContext("username__similar=" + "adm%25&email=a@example.com")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE "email" = \$1 AND "username" SIMILAR TO \$2$`).
+		WithArgs("a@example.com", "adm%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err := s.db.Model(&User{}).Scopes(FilterByQuery(ctx, FILTER)).Find(&users).Error
+	s.NoError(err)
+}
+
+// TestRegisterOperatorInvalid checks the rejected registrations.
+func (s *TestSuite) TestRegisterOperatorInvalid() {
+	builder := func(_ *gorm.DB, _ Config, f Filter) clause.Expression { return nil }
+	s.Panics(func() { RegisterOperator("eq", builder) })
+	s.Panics(func() { RegisterOperator("gte_or_null", builder) })
+	s.Panics(func() { RegisterOperator("", builder) })
+	s.Panics(func() { RegisterOperator("between", nil) })
+}
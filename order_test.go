@@ -0,0 +1,198 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/gorm"
+)
+
+type Player struct {
+	Id    int64
+	Name  string `filter:"filterable"`
+	Score *int   `filter:"filterable"`
+}
+
+// TestOrNullFilterWithNullsOrder checks that the rows matched by an or-null
+// filter are placed according to the requested nulls order.
+func (s *TestSuite) TestOrNullFilterWithNullsOrder() {
+	var players []Player
+	ctx := newTestContext("score__gte_or_null=10&order_by=score&order_nulls=last")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "players" WHERE \("score" >= \$1 OR "score" IS NULL\) ORDER BY "players"\."score" DESC NULLS LAST$`).
+		WithArgs("10").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "score"}))
+	err := s.db.Model(&Player{}).Scopes(FilterByQuery(ctx, FILTER|ORDER_BY)).Find(&players).Error
+	s.NoError(err)
+}
+
+// TestNullsOrderFirstAsc checks NULLS FIRST with an ascending order.
+func (s *TestSuite) TestNullsOrderFirstAsc() {
+	var players []Player
+	ctx := newTestContext("order_by=score&order_direction=asc&order_nulls=first")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "players" ORDER BY "players"\."score" ASC NULLS FIRST$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "score"}))
+	err := s.db.Model(&Player{}).Scopes(FilterByQuery(ctx, ORDER_BY)).Find(&players).Error
+	s.NoError(err)
+}
+
+// TestSortOverOrderBy checks which of "sort" and "order_by" is applied when both are sent.
+func (s *TestSuite) TestSortOverOrderBy() {
+	var players []Player
+	ctx := newTestContext("sort=-score&order_by=name&order_direction=asc")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "players" ORDER BY "players"\."score" DESC$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "score"}))
+	err := s.db.Model(&Player{}).Scopes(FilterByQuery(ctx, ORDER_BY)).Find(&players).Error
+	s.NoError(err)
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "players" ORDER BY "players"\."name"$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "score"}))
+	err = s.db.Model(&Player{}).Scopes(FilterByConfig(ctx, Config{Flags: ORDER_BY, PreferOrderBy: true})).Find(&players).Error
+	s.NoError(err)
+}
+
+// TestMaxOrderColumns checks that the order columns over the limit are dropped.
+func (s *TestSuite) TestMaxOrderColumns() {
+	var players []Player
+	config := Config{Flags: ORDER_BY, MaxOrderColumns: 2}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "players" ORDER BY "players"\."score" DESC,"players"\."name"$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "score"}))
+	err := s.db.Model(&Player{}).Scopes(FilterByConfig(newTestContext("sort=-score,name"), config)).Find(&players).Error
+	s.NoError(err)
+
+	ctx := newTestContext("order_by=score,name,id&order_direction=asc")
+	s.mock.ExpectQuery(`^SELECT \* FROM "players" ORDER BY "players"\."score","players"\."name"$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "score"}))
+	err = s.db.Model(&Player{}).Scopes(FilterByConfig(ctx, config)).Find(&players).Error
+	s.NoError(err)
+
+	config.Strict = true
+	_, err = ParseQuery(ctx, &Player{}, config)
+	s.EqualError(err, "filter: order_by: too many order columns")
+}
+
+// TestOrNullsLast checks that the rows matched by an or-null filter are ordered last.
+func (s *TestSuite) TestOrNullsLast() {
+	var players []Player
+	config := Config{Flags: FILTER | ORDER_BY, OrNullsLast: true}
+
+	for _, direction := range []string{"asc", "desc"} {
+		ctx := newTestContext("score__gte_or_null=10&order_by=score&order_direction=" + direction)
+		s.mock.ExpectQuery(`^SELECT \* FROM "players" WHERE \("score" >= \$1 OR "score" IS NULL\) ORDER BY "players"\."score" ` + strings.ToUpper(direction) + ` NULLS LAST$`).
+			WithArgs("10").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "score"}))
+		err := s.db.Model(&Player{}).Scopes(FilterByConfig(ctx, config)).Find(&players).Error
+		s.NoError(err, direction)
+	}
+
+	ctx := newTestContext("score__gte_or_null=10&order_by=score&order_nulls=first")
+	s.mock.ExpectQuery(`^SELECT \* FROM "players" WHERE \("score" >= \$1 OR "score" IS NULL\) ORDER BY "players"\."score" DESC NULLS FIRST$`).
+		WithArgs("10").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "score"}))
+	err := s.db.Model(&Player{}).Scopes(FilterByConfig(ctx, config)).Find(&players).Error
+	s.NoError(err)
+}
+
+// TestNoOrder checks that the no-order param disables the default order.
+func (s *TestSuite) TestNoOrder() {
+	var players []Player
+	config := Config{Flags: ALL, NoOrder: "order=none", Strict: true}
+
+	s.mock.ExpectQuery(`^SELECT count\(\*\) FROM "players"`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectQuery(`^SELECT \* FROM "players" LIMIT 20$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "score"}))
+	err := s.db.Model(&Player{}).Scopes(FilterByConfig(newTestContext("order=none"), config)).Find(&players).Error
+	s.NoError(err)
+
+	s.mock.ExpectQuery(`^SELECT count\(\*\) FROM "players"`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectQuery(`^SELECT \* FROM "players" ORDER BY "players"\."created_at" DESC LIMIT 20$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "score"}))
+	err = s.db.Model(&Player{}).Scopes(FilterByConfig(newTestContext("order=name"), Config{Flags: ALL, NoOrder: "order=none"})).Find(&players).Error
+	s.NoError(err)
+}
+
+// TestReverseOrder checks that the reverse param inverts every order column.
+func (s *TestSuite) TestReverseOrder() {
+	var players []Player
+	ctx := newTestContext("order_by=-score,name&order_direction=asc&reverse=true")
+	config := Config{Flags: ORDER_BY | FILTER, ReverseParam: "reverse", Strict: true}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "players" ORDER BY "players"\."score","players"\."name" DESC$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "score"}))
+	err := s.db.Model(&Player{}).Scopes(FilterByConfig(ctx, config)).Find(&players).Error
+	s.NoError(err)
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "players" ORDER BY "players"\."name"$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "score"}))
+	err = s.db.Model(&Player{}).Scopes(FilterByConfig(newTestContext("order_by=name&order_direction=desc&reverse=1"), config)).Find(&players).Error
+	s.NoError(err)
+}
+
+// TestOrderInList checks that a column can be ordered by the position of its
+// values in its in filter.
+func (s *TestSuite) TestOrderInList() {
+	var tickets []Ticket
+	ctx := newTestContext("id__in=3,1,2&order_by=id:in_order,state&order_direction=asc")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "tickets" WHERE "id" IN \(\$1,\$2,\$3\) ORDER BY array_position\(\$4, "tickets"\."id"\), "tickets"\."state"$`).
+		WithArgs("3", "1", "2", `{"3","1","2"}`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "state"}))
+	err := s.db.Model(&Ticket{}).Scopes(FilterByQuery(ctx, FILTER|ORDER_BY)).Find(&tickets).Error
+	s.NoError(err)
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "tickets" ORDER BY "tickets"\."id"$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "state"}))
+	err = s.db.Model(&Ticket{}).Scopes(FilterByQuery(newTestContext("order_by=id:in_order&order_direction=asc"), FILTER|ORDER_BY)).Find(&tickets).Error
+	s.NoError(err)
+}
+
+// TestOrderInvalidIdentifier checks that the order columns which are not
+// valid identifiers are dropped, and reported in strict mode.
+func (s *TestSuite) TestOrderInvalidIdentifier() {
+	var players []Player
+	ctx := newTestContext("order_by=" + url.QueryEscape(`name";drop,score`))
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "players" ORDER BY "players"\."score" DESC$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "score"}))
+	err := s.db.Model(&Player{}).Scopes(FilterByQuery(ctx, ORDER_BY)).Find(&players).Error
+	s.NoError(err)
+
+	config := Config{Flags: ORDER_BY, Strict: true, MaxIdentifierLength: 8}
+	for _, column := range []string{`name";drop`, "name.id", strings.Repeat("s", 9)} {
+		_, err := ParseQuery(newTestContext("order_by="+url.QueryEscape(column)), &Player{}, config)
+		s.EqualError(err, "filter: order_by: invalid column", column)
+	}
+	_, err = ParseQuery(newTestContext("order_by=score"), &Player{}, config)
+	s.NoError(err)
+}
+
+// TestNullsOrderEmulated checks that the NULL values are ordered by whether
+// the column is NULL on the databases without NULLS FIRST/LAST, the or-null
+// filters included.
+func (s *TestSuite) TestNullsOrderEmulated() {
+	var players []Player
+	db, err := gorm.Open(mysqlDialector{s.db.Dialector}, &gorm.Config{})
+	s.Require().NoError(err)
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "players" ORDER BY "players"\."score" IS NULL DESC,"players"\."score"$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "score"}))
+	err = db.Model(&Player{}).Scopes(FilterByConfig(newTestContext("order_by=score&order_direction=asc&order_nulls=first"), Config{Flags: ORDER_BY})).Find(&players).Error
+	s.NoError(err)
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "players" WHERE \("score" >= \$1 OR "score" IS NULL\) ORDER BY "players"\."score" IS NULL,"players"\."score" DESC$`).
+		WithArgs("10").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "score"}))
+	err = db.Model(&Player{}).Scopes(FilterByConfig(newTestContext("score__gte_or_null=10&order_by=score"), Config{Flags: FILTER | ORDER_BY, OrNullsLast: true})).Find(&players).Error
+	s.NoError(err)
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm/clause"
+)
+
+// RangeOverlap are the columns of the start and the end of the range of a
+// row, eg : the stay of a booking.
+type RangeOverlap struct {
+	Start string
+	End   string
+}
+
+// rangeOverlapFilter returns the filter of the param key if it is registered
+// in config.RangeOverlaps, along with an error if value is not a range of
+// times, eg : "2022-03-01,2022-03-10". The bounds are inclusive.
+func (config Config) rangeOverlapFilter(key, value string) (Filter, bool, error) {
+	overlap, ok := config.RangeOverlaps[key]
+	if !ok {
+		return Filter{}, false, nil
+	}
+	startValue, endValue, ok := strings.Cut(value, ",")
+	if !ok {
+		return Filter{}, true, errors.New("invalid range")
+	}
+	start, err := config.parseTime(strings.TrimSpace(startValue))
+	if err != nil {
+		return Filter{}, true, err
+	}
+	end, err := config.parseTime(strings.TrimSpace(endValue))
+	if err != nil {
+		return Filter{}, true, err
+	}
+	if end.Before(start) {
+		return Filter{}, true, errors.New("invalid range")
+	}
+	// The rows start before the end of the range and end after its start.
+	return Filter{Param: key, Operator: "eq", Value: value, expr: clause.Expr{
+		SQL:  "? <= ? AND ? >= ?",
+		Vars: []interface{}{clause.Column{Name: overlap.Start}, end, clause.Column{Name: overlap.End}, start},
+	}}, true, nil
+}
+
+// betweenColumnsSuffix is the operator of the filters bounding a value with
+// two columns, eg : "30__between_cols=min_age,max_age".
+const betweenColumnsSuffix = "between_cols"
+
+// betweenColumnsFilter returns the filter of key if it bounds a value with two
+// columns of config.BetweenColumns, along with an error if value is not two
+// of these columns or if the bounded value is neither a number nor a time.
+func (config Config) betweenColumnsFilter(key, value string) (Filter, bool, error) {
+	literal, suffix, found := cutSuffix(key)
+	if !found || suffix != betweenColumnsSuffix || len(config.BetweenColumns) == 0 {
+		return Filter{}, false, nil
+	}
+	minColumn, maxColumn, ok := strings.Cut(value, ",")
+	if !ok || strings.Contains(maxColumn, ",") {
+		return Filter{}, true, errors.New("expects 2 columns")
+	}
+	minColumn, maxColumn = strings.TrimSpace(minColumn), strings.TrimSpace(maxColumn)
+	for _, column := range []string{minColumn, maxColumn} {
+		if !contains(config.BetweenColumns, column) {
+			return Filter{}, true, errors.New("unknown column " + column)
+		}
+	}
+	bound, err := config.betweenColumnsValue(literal)
+	if err != nil {
+		return Filter{}, true, err
+	}
+	return Filter{Param: literal, Operator: betweenColumnsSuffix, Value: value, expr: clause.Expr{
+		SQL:  "? <= ? AND ? <= ?",
+		Vars: []interface{}{clause.Column{Name: minColumn}, bound, bound, clause.Column{Name: maxColumn}},
+	}}, true, nil
+}
+
+// betweenColumnsValue returns the value bounded by two columns, an integer, a
+// float or a time, eg : "30" or "2022-03-01", which the columns can be
+// compared to, or an error.
+func (config Config) betweenColumnsValue(literal string) (interface{}, error) {
+	if integer, err := strconv.ParseInt(literal, 10, 64); err == nil {
+		return integer, nil
+	}
+	if float, err := strconv.ParseFloat(literal, 64); err == nil && !math.IsNaN(float) && !math.IsInf(float, 0) {
+		return float, nil
+	}
+	if t, err := config.parseTime(literal); err == nil {
+		return t, nil
+	}
+	return nil, errors.New("invalid value")
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type Booking struct {
+	Id      int64
+	Room    string    `filter:"filterable"`
+	StartAt time.Time `filter:"filterable"`
+	EndAt   time.Time `filter:"filterable"`
+}
+
+// TestFiltersRangeOverlap checks that the rows whose range overlaps the given
+// range are matched.
+func (s *TestSuite) TestFiltersRangeOverlap() {
+	var bookings []Booking
+	ctx := newTestContext("overlaps=2022-03-01,2022-03-10&room=101")
+	config := Config{Flags: FILTER, RangeOverlaps: map[string]RangeOverlap{
+		"overlaps": {Start: "start_at", End: "end_at"},
+	}}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "bookings" WHERE \("start_at" <= \$1 AND "end_at" >= \$2\) AND "room" = \$3$`).
+		WithArgs(time.Date(2022, 3, 10, 0, 0, 0, 0, time.UTC), time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC), "101").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "room", "start_at", "end_at"}))
+	err := s.db.Model(&Booking{}).Scopes(FilterByConfig(ctx, config)).Find(&bookings).Error
+	s.NoError(err)
+
+	config.Strict = true
+	_, err = ParseQuery(newTestContext("overlaps=2022-03-10,2022-03-01"), &Booking{}, config)
+	s.EqualError(err, "filter: overlaps: invalid range")
+}
+
+type AgeGroup struct {
+	Id     int64
+	Name   string `filter:"filterable"`
+	MinAge int64
+	MaxAge int64
+}
+
+// TestFiltersBetweenColumns checks that a value is bounded by two allowed
+// columns.
+func (s *TestSuite) TestFiltersBetweenColumns() {
+	var groups []AgeGroup
+	ctx := newTestContext("30__between_cols=min_age,max_age")
+	config := Config{Flags: FILTER, BetweenColumns: []string{"min_age", "max_age"}}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "age_groups" WHERE "min_age" <= \$1 AND \$2 <= "max_age"$`).
+		WithArgs(int64(30), int64(30)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "min_age", "max_age"}))
+	err := s.db.Model(&AgeGroup{}).Scopes(FilterByConfig(ctx, config)).Find(&groups).Error
+	s.NoError(err)
+
+	config.Strict = true
+	for query, reason := range map[string]string{
+		"30__between_cols=min_age,password": "filter: 30__between_cols: unknown column password",
+		"30__between_cols=min_age":          "filter: 30__between_cols: expects 2 columns",
+		"abc__between_cols=min_age,max_age": "filter: abc__between_cols: invalid value",
+	} {
+		_, err = ParseQuery(newTestContext(query), &AgeGroup{}, config)
+		s.EqualError(err, reason, query)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// PaginateScope returns a scope paginating any query with the page, limit and
+// all params of the request, like the PAGINATE capability, and a function
+// returning the number of rows counted by the scope once it has run.
+// Example:
+//
+//	paginate, count := filter.PaginateScope(c, filter.Config{ExportLimit: 1000})
+//	err := db.Model(&Customer{}).Joins("JOIN orders ON orders.customer_id = customers.id").
+//		Scopes(paginate).Find(&customers).Error
+//	total := count()
+func PaginateScope(c *gin.Context, config Config) (func(db *gorm.DB) *gorm.DB, func() int64) {
+	var count int64
+	scope := func(db *gorm.DB) *gorm.DB {
+		params := config.Defaults
+		setDefault(&params)
+		if err := bindParams(c.Request.URL.Query(), &params, config.FilterReservedParams); err != nil {
+			db.AddError(err)
+			return db
+		}
+		normalizePagination(&params)
+		applyOffset(&params, c.Request.URL.Query(), config.PreferPage)
+
+		var err error
+		if count, err = countRows(db); err != nil {
+			db.AddError(err)
+			return db
+		}
+		if params.All && config.ExportLimit > 0 {
+			return exportAll(c, db, count, config.ExportLimit)
+		}
+		return paginate(c, db, count, params)
+	}
+	return scope, func() int64 { return count }
+}
+
+// CountByConfig counts the rows of db matched by the filters and the search
+// of the request, eg : for a "count matching" endpoint. The order and the
+// pagination are never applied, whatever the flags of config and the clauses
+// of db.
+// Example:
+//
+//	count, err := filter.CountByConfig(c, db.Model(&UserModel{}), filter.Config{Flags: filter.ALL})
+func CountByConfig(c *gin.Context, db *gorm.DB, config Config) (int64, error) {
+	config.Flags &^= PAGINATE | ORDER_BY
+	return countRows(db.Scopes(FilterByConfig(c, config)))
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TestPaginateScope checks the pagination of a hand-built query.
+func (s *TestSuite) TestPaginateScope() {
+	var users []User
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = &http.Request{
+		URL: &url.URL{
+			RawQuery: "page=2&limit=5&username=ignored",
+		},
+	}
+
+	scope, count := PaginateScope(ctx, Config{})
+	s.mock.ExpectQuery(`^SELECT count\(\*\) FROM "users" WHERE email LIKE \$1$`).
+		WithArgs("%@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE email LIKE \$1 LIMIT 5 OFFSET 5$`).
+		WithArgs("%@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err := s.db.Model(&User{}).Where("email LIKE ?", "%@example.com").Scopes(scope).Find(&users).Error
+	s.NoError(err)
+	s.Equal(int64(12), count())
+	s.Equal("3", w.Header().Get("X-Paginate-Pages"))
+}
+
+type Subscriber struct {
+	Id        int64
+	Email     string `filter:"filterable"`
+	DeletedAt gorm.DeletedAt
+}
+
+// TestPaginateUnscoped checks that the count keeps the soft delete scoping of the query.
+func (s *TestSuite) TestPaginateUnscoped() {
+	var subscribers []Subscriber
+	ctx := newTestContext("email=a@example.com&order_by=id")
+
+	s.mock.ExpectQuery(`^SELECT count\(\*\) FROM "subscribers" WHERE "email" = \$1$`).
+		WithArgs("a@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectQuery(`^SELECT \* FROM "subscribers" WHERE "email" = \$1 ORDER BY "subscribers"\."id" DESC LIMIT 20$`).
+		WithArgs("a@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "deleted_at"}))
+	err := s.db.Unscoped().Model(&Subscriber{}).Scopes(FilterByQuery(ctx, ALL)).Find(&subscribers).Error
+	s.NoError(err)
+
+	s.mock.ExpectQuery(`^SELECT count\(\*\) FROM "subscribers" WHERE "email" = \$1 AND "subscribers"\."deleted_at" IS NULL$`).
+		WithArgs("a@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectQuery(`^SELECT \* FROM "subscribers" WHERE "email" = \$1 AND "subscribers"\."deleted_at" IS NULL ORDER BY "subscribers"\."id" DESC LIMIT 20$`).
+		WithArgs("a@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "deleted_at"}))
+	err = s.db.Model(&Subscriber{}).Scopes(FilterByQuery(ctx, ALL)).Find(&subscribers).Error
+	s.NoError(err)
+}
+
+// TestCountByConfig checks that the count applies the filters and the search,
+// but neither the order nor the pagination.
+func (s *TestSuite) TestCountByConfig() {
+	ctx := newTestContext("username=sampleUser&search=john&order_by=email&page=2&limit=5")
+
+	s.mock.ExpectQuery(`^SELECT count\(\*\) FROM "users" WHERE "username" = \$1 AND \("username" LIKE \$2 OR "full_name" LIKE \$3\)$`).
+		WithArgs("sampleUser", "%john%", "%john%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+	count, err := CountByConfig(ctx, s.db.Model(&User{}).Order("id").Limit(10).Offset(20), Config{Flags: ALL})
+	s.NoError(err)
+	s.Equal(int64(7), count)
+}
+
+// TestPaginateOffsetPrecedence checks that the offset wins over the page
+// unless the config prefers the page.
+func (s *TestSuite) TestPaginateOffsetPrecedence() {
+	var users []User
+	for preferPage, offset := range map[bool]string{false: "OFFSET 15", true: "OFFSET 10"} {
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = &http.Request{URL: &url.URL{RawQuery: "page=3&offset=15&limit=5"}}
+
+		s.mock.ExpectQuery(`^SELECT count\(\*\) FROM "users"$`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(40))
+		s.mock.ExpectQuery(`^SELECT \* FROM "users" LIMIT 5 ` + offset + `$`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+		err := s.db.Model(&User{}).Scopes(FilterByConfig(ctx, Config{Flags: PAGINATE, PreferPage: preferPage})).Find(&users).Error
+		s.NoError(err, offset)
+		if !preferPage {
+			s.Equal("4", w.Header().Get("X-Paginate-Current"))
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm/clause"
+)
+
+// percentileSuffix prefixes the operators of the percentile filters, eg :
+// "score__percentile_gt=0.9".
+const percentileSuffix = "percentile_"
+
+// percentileFilter returns the filter of the param of key listed in
+// config.Percentiles if key is a percentile filter, eg :
+// "score__percentile_gte=0.9", along with an error if the percentile is not
+// between 0 and 1.
+func (config Config) percentileFilter(key, value string, modelType reflect.Type) (Filter, bool, error) {
+	param, suffix, found := cutSuffix(key)
+	if !found || !contains(config.Percentiles, param) {
+		return Filter{}, false, nil
+	}
+	operator, ok := strings.CutPrefix(suffix, percentileSuffix)
+	symbol, known := comparisonSymbols[operator]
+	if !ok || !known {
+		return Filter{}, false, nil
+	}
+	s, err := config.parseSchema(modelType)
+	if err != nil || s.PrioritizedPrimaryField == nil {
+		return Filter{}, false, nil
+	}
+	column := ""
+	for i := 0; i < modelType.NumField(); i++ {
+		field := modelType.Field(i)
+		if name, fieldColumn, ok := fieldParam(field); ok && name == param && isNumeric(field.Type) {
+			column = fieldColumn
+			break
+		}
+	}
+	if column == "" {
+		return Filter{}, false, nil
+	}
+	percentile, err := strconv.ParseFloat(value, 64)
+	if err != nil || percentile < 0 || percentile > 1 {
+		return Filter{}, true, errors.New("invalid percentile")
+	}
+
+	primaryKey := clause.Column{Name: s.PrioritizedPrimaryField.DBName}
+	vars := []interface{}{
+		clause.Column{Table: s.Table, Name: primaryKey.Name},
+		primaryKey,
+		primaryKey,
+		clause.Column{Name: column},
+		clause.Table{Name: s.Table},
+	}
+	// The rows without value are left out of the ranking, Postgres sorting
+	// them last, and so are the soft deleted rows, as they are of the query.
+	ranked := "FROM ? WHERE ? IS NOT NULL"
+	vars = append(vars, clause.Column{Name: column})
+	for _, field := range s.Fields {
+		if field.DBName != "" && isSoftDelete(field.FieldType) {
+			ranked += " AND ? IS NULL"
+			vars = append(vars, clause.Column{Name: field.DBName})
+			break
+		}
+	}
+	return Filter{
+		Param:    param,
+		Operator: suffix,
+		Value:    value,
+		expr: clause.Expr{
+			SQL:  "? IN (SELECT ? FROM (SELECT ?, percent_rank() OVER (ORDER BY ?) AS percentile " + ranked + ") AS ranked WHERE percentile " + symbol + " ?)",
+			Vars: append(vars, percentile),
+		},
+	}, true, nil
+}
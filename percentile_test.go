@@ -0,0 +1,63 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// TestFiltersPercentile checks that the rows are filtered by the percent rank
+// of their score, the rows without score being left out.
+func (s *TestSuite) TestFiltersPercentile() {
+	var players []Player
+	ctx := newTestContext("score__percentile_gt=0.9&name=bob")
+	config := Config{Flags: FILTER, Percentiles: []string{"score"}}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "players" WHERE "name" = \$1 AND "players"\."id" IN \(SELECT "id" FROM \(SELECT "id", percent_rank\(\) OVER \(ORDER BY "score"\) AS percentile FROM "players" WHERE "score" IS NOT NULL\) AS ranked WHERE percentile > \$2\)$`).
+		WithArgs("bob", 0.9).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "score"}))
+	err := s.db.Model(&Player{}).Scopes(FilterByConfig(ctx, config)).Find(&players).Error
+	s.NoError(err)
+
+	config.Strict = true
+	_, err = ParseQuery(newTestContext("score__percentile_gt=90"), &Player{}, config)
+	s.EqualError(err, "filter: score__percentile_gt: invalid percentile")
+}
+
+// TestFiltersPercentileNamingStrategy checks that the ranked table follows
+// the naming strategy of the database.
+func (s *TestSuite) TestFiltersPercentileNamingStrategy() {
+	var players []Player
+	s.db.Config.NamingStrategy = schema.NamingStrategy{SingularTable: true}
+	config := Config{Flags: FILTER, Percentiles: []string{"score"}}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "player" WHERE "player"\."id" IN \(SELECT "id" FROM \(SELECT "id", percent_rank\(\) OVER \(ORDER BY "score"\) AS percentile FROM "player" WHERE "score" IS NOT NULL\) AS ranked WHERE percentile > \$1\)$`).
+		WithArgs(0.9).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "score"}))
+	err := s.db.Model(&Player{}).Scopes(FilterByConfig(newTestContext("score__percentile_gt=0.9"), config)).Find(&players).Error
+	s.NoError(err)
+}
+
+type Contestant struct {
+	Id        int64
+	Score     int `filter:"filterable"`
+	DeletedAt gorm.DeletedAt
+}
+
+// TestFiltersPercentileSoftDelete checks that the soft deleted rows are left
+// out of the ranking.
+func (s *TestSuite) TestFiltersPercentileSoftDelete() {
+	var contestants []Contestant
+	config := Config{Flags: FILTER, Percentiles: []string{"score"}}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "contestants" WHERE \("contestants"\."id" IN \(SELECT "id" FROM \(SELECT "id", percent_rank\(\) OVER \(ORDER BY "score"\) AS percentile FROM "contestants" WHERE "score" IS NOT NULL AND "deleted_at" IS NULL\) AS ranked WHERE percentile >= \$1\)\) AND "contestants"\."deleted_at" IS NULL$`).
+		WithArgs(0.5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "score", "deleted_at"}))
+	err := s.db.Model(&Contestant{}).Scopes(FilterByConfig(newTestContext("score__percentile_gte=0.5"), config)).Find(&contestants).Error
+	s.NoError(err)
+}
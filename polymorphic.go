@@ -0,0 +1,131 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"net/url"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+var schemaCache = &sync.Map{}
+
+// parseSchema parses the schema of modelType, or of the type it points to,
+// with the naming strategy and the cache of config.db, or with the default
+// ones outside a scope, eg : with ParseQuery.
+func (config Config) parseSchema(modelType reflect.Type) (*schema.Schema, error) {
+	for modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+	model := reflect.New(modelType).Interface()
+	if config.db == nil {
+		return schema.Parse(model, schemaCache, schema.NamingStrategy{})
+	}
+	stmt := &gorm.Statement{DB: config.db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+	return stmt.Schema, nil
+}
+
+// polymorphicColumns are the type and id columns of a polymorphic association
+// along with the values stored in the type column for each owner.
+type polymorphicColumns struct {
+	typeColumn string
+	idColumn   string
+	// typeValues maps the owner model names and their stored values to the
+	// stored values.
+	typeValues map[string]string
+}
+
+// polymorphicAssociations returns the polymorphic associations of modelType
+// declared by the `polymorphic` gorm tag of config.PolymorphicOwners, sorted
+// by type column.
+func (config Config) polymorphicAssociations(modelType reflect.Type) []polymorphicColumns {
+	byTypeColumn := map[string]*polymorphicColumns{}
+	for _, owner := range config.PolymorphicOwners {
+		ownerSchema, err := config.parseSchema(reflect.TypeOf(owner))
+		if err != nil {
+			continue
+		}
+		for _, relationship := range ownerSchema.Relationships.Relations {
+			polymorphic := relationship.Polymorphic
+			if polymorphic == nil || relationship.FieldSchema.ModelType != modelType {
+				continue
+			}
+			columns, ok := byTypeColumn[polymorphic.PolymorphicType.DBName]
+			if !ok {
+				columns = &polymorphicColumns{
+					typeColumn: polymorphic.PolymorphicType.DBName,
+					idColumn:   polymorphic.PolymorphicID.DBName,
+					typeValues: map[string]string{},
+				}
+				byTypeColumn[columns.typeColumn] = columns
+			}
+			columns.typeValues[ownerSchema.Name] = polymorphic.Value
+			columns.typeValues[polymorphic.Value] = polymorphic.Value
+		}
+	}
+
+	associations := make([]polymorphicColumns, 0, len(byTypeColumn))
+	for _, columns := range byTypeColumn {
+		associations = append(associations, *columns)
+	}
+	sort.Slice(associations, func(i, j int) bool {
+		return associations[i].typeColumn < associations[j].typeColumn
+	})
+	return associations
+}
+
+// polymorphicFilters returns the filters on the type and id columns of the
+// polymorphic associations of modelType, eg : "commentable_type=Post" and
+// "commentable_id=5", along with the params they handle and the errors of the
+// filters rejected like in parseFilters. The owner model name sent as type is
+// replaced by the value GORM stores for it.
+func (config Config) polymorphicFilters(c *gin.Context, values url.Values, modelType reflect.Type) ([]Filter, []string, []error) {
+	var (
+		filters []Filter
+		params  []string
+		errs    []error
+	)
+	add := func(column, value string) {
+		if config.disabled(column) {
+			return
+		}
+		if !contains(params, column) {
+			params = append(params, column)
+		}
+		filter := Filter{Param: column, Column: column, Operator: "eq", Value: value}
+		if !config.authorized(c, filter.Param, FILTER) {
+			errs = append(errs, &ParamError{Param: column, Reason: "unauthorized filter"})
+			return
+		}
+		ok, err := filter.rewrite(config)
+		if err != nil {
+			errs = append(errs, &ParamError{Param: column, Reason: err.Error()})
+		}
+		if ok {
+			filters = append(filters, filter)
+		}
+	}
+	for _, association := range config.polymorphicAssociations(modelType) {
+		for _, value := range values[association.typeColumn] {
+			if stored, ok := association.typeValues[value]; ok {
+				value = stored
+			}
+			add(association.typeColumn, value)
+		}
+		for _, value := range values[association.idColumn] {
+			add(association.idColumn, value)
+		}
+	}
+	return filters, params, errs
+}
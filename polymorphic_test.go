@@ -0,0 +1,96 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/schema"
+)
+
+type Post struct {
+	Id       int64
+	Comments []Comment `gorm:"polymorphic:Commentable;"`
+}
+
+type Video struct {
+	Id       int64
+	Comments []Comment `gorm:"polymorphic:Commentable;polymorphicValue:clip"`
+}
+
+type Comment struct {
+	Id              int64
+	Body            string
+	CommentableID   int64
+	CommentableType string
+}
+
+// TestFiltersPolymorphic checks the type and id conditions of a polymorphic association.
+func (s *TestSuite) TestFiltersPolymorphic() {
+	config := Config{Flags: FILTER, PolymorphicOwners: []interface{}{&Post{}, &Video{}}}
+	for typeParam, typeValue := range map[string]string{"Post": "posts", "Video": "clip"} {
+		var comments []Comment
+		ctx := newTestContext("commentable_type=" + typeParam + "&commentable_id=5")
+
+		s.mock.ExpectQuery(`^SELECT \* FROM "comments" WHERE "commentable_type" = \$1 AND "commentable_id" = \$2$`).
+			WithArgs(typeValue, "5").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "body", "commentable_id", "commentable_type"}))
+		err := s.db.Model(&Comment{}).Scopes(FilterByConfig(ctx, config)).Find(&comments).Error
+		s.NoError(err)
+	}
+}
+
+// TestFiltersPolymorphicNamingStrategy checks that the stored type values
+// follow the naming strategy of the database.
+func (s *TestSuite) TestFiltersPolymorphicNamingStrategy() {
+	var comments []Comment
+	s.db.Config.NamingStrategy = schema.NamingStrategy{TablePrefix: "app_"}
+	config := Config{Flags: FILTER, PolymorphicOwners: []interface{}{&Post{}}}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "app_comments" WHERE "commentable_type" = \$1$`).
+		WithArgs("app_posts").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "body", "commentable_id", "commentable_type"}))
+	err := s.db.Model(&Comment{}).Scopes(FilterByConfig(newTestContext("commentable_type=Post"), config)).Find(&comments).Error
+	s.NoError(err)
+}
+
+// TestFiltersPolymorphicChecks checks that the polymorphic filters are
+// authorized, disabled and rewritten like the other filters.
+func (s *TestSuite) TestFiltersPolymorphicChecks() {
+	var (
+		comments   []Comment
+		authorized []string
+	)
+	ctx := newTestContext("commentable_type=Post&commentable_id=5")
+	config := Config{
+		Flags:             FILTER,
+		PolymorphicOwners: []interface{}{&Post{}},
+		DisabledParams:    []string{"commentable_id"},
+		AuthorizeField: func(c *gin.Context, field string, capability int) bool {
+			authorized = append(authorized, field)
+			return false
+		},
+	}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "comments"$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "body", "commentable_id", "commentable_type"}))
+	err := s.db.Model(&Comment{}).Scopes(FilterByConfig(ctx, config)).Find(&comments).Error
+	s.NoError(err)
+	s.Equal([]string{"commentable_type"}, authorized)
+
+	config.Strict, config.CollectErrors = true, true
+	_, err = ParseQuery(ctx, &Comment{}, config)
+	s.EqualError(err, "filter: commentable_id: unknown filter\nfilter: commentable_type: unauthorized filter")
+
+	config = Config{Flags: FILTER, PolymorphicOwners: []interface{}{&Post{}}, RewriteFilter: func(param, operator, value string) (string, string, bool) {
+		return operator, value, param != "commentable_id"
+	}}
+	s.mock.ExpectQuery(`^SELECT \* FROM "comments" WHERE "commentable_type" = \$1$`).
+		WithArgs("posts").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "body", "commentable_id", "commentable_type"}))
+	err = s.db.Model(&Comment{}).Scopes(FilterByConfig(ctx, config)).Find(&comments).Error
+	s.NoError(err)
+}
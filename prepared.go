@@ -0,0 +1,120 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// PreparedFilter is a filter shape compiled once for a model. Only the values
+// are read from each request, the params are not matched against the model
+// again.
+type PreparedFilter struct {
+	keys    []string
+	filters map[string][]Filter
+	config  Config
+}
+
+// Prepare compiles the filters of model for the given query param keys, eg :
+// "username" or "created_at__gte". Keys which don't match a filterable field,
+// or only disabled ones, are rejected.
+// Example:
+//
+//	var usersFilter = filter.MustPrepare(&UserModel{}, filter.Config{}, "username", "created_at__gte")
+//
+//	db.Model(&UserModel{}).Scopes(usersFilter.Scope(c)).Find(&users)
+func Prepare(model interface{}, config Config, keys ...string) (*PreparedFilter, error) {
+	modelType := reflect.TypeOf(model)
+	if model == nil || modelType.Kind() != reflect.Ptr || modelType.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("filter: model must be a pointer to a struct")
+	}
+
+	prepared := &PreparedFilter{filters: make(map[string][]Filter, len(keys)), config: config}
+	for _, key := range keys {
+		filters := config.withoutDisabled(matchFilters(key, eq, modelType.Elem(), config))
+		if len(filters) == 0 {
+			return nil, errors.New("filter: " + key + " doesn't match any filterable field")
+		}
+		prepared.keys = append(prepared.keys, key)
+		prepared.filters[key] = filters
+	}
+	return prepared, nil
+}
+
+// MustPrepare is like Prepare but panics if a key is invalid.
+func MustPrepare(model interface{}, config Config, keys ...string) *PreparedFilter {
+	prepared, err := Prepare(model, config, keys...)
+	if err != nil {
+		panic(err)
+	}
+	return prepared
+}
+
+// Scope binds the query params of the request to the prepared filters. Like
+// the other scopes, the invalid or unauthorized values are ignored, unless
+// the config is strict.
+func (p *PreparedFilter) Scope(c *gin.Context) func(db *gorm.DB) *gorm.DB {
+	return p.bind(c, c.Request.URL.Query())
+}
+
+// Bind binds values to the prepared filters. Keys missing from values are not
+// filtered.
+func (p *PreparedFilter) Bind(values url.Values) func(db *gorm.DB) *gorm.DB {
+	return p.bind(nil, values)
+}
+
+func (p *PreparedFilter) bind(c *gin.Context, values url.Values) func(db *gorm.DB) *gorm.DB {
+	config := p.config.withRequestTimezone(c)
+	var (
+		filters []Filter
+		errs    []error
+	)
+	for _, key := range p.keys {
+		for i, value := range values[key] {
+			for _, filter := range p.filters[key] {
+				if !config.authorized(c, filter.Param, FILTER) {
+					errs = append(errs, &ParamError{Param: key, Reason: "unauthorized filter"})
+					continue
+				}
+				var (
+					ok  bool
+					err error
+				)
+				if listOperators[filter.Operator] {
+					if i > 0 {
+						// Every value has been bound with the first one.
+						continue
+					}
+					ok, err = filter.bindList(c, splitList(values[key]), config)
+				} else {
+					ok, err = filter.bind(c, value, config)
+				}
+				if ok {
+					ok, err = filter.rewrite(config)
+				}
+				if err != nil {
+					errs = append(errs, &ParamError{Param: key, Reason: err.Error()})
+				}
+				if ok {
+					filters = append(filters, filter)
+				}
+			}
+		}
+	}
+	err := config.strictError(errs)
+	return func(db *gorm.DB) *gorm.DB {
+		if err != nil {
+			db.AddError(err)
+			return db
+		}
+		return expressionByFilters(db, filters, config)
+	}
+}
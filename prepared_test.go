@@ -0,0 +1,76 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"net/url"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+)
+
+// TestPreparedFilterReuse checks that a prepared filter is bound again for each request.
+func (s *TestSuite) TestPreparedFilterReuse() {
+	prepared, err := Prepare(&Player{}, Config{}, "name", "score__gte")
+	s.NoError(err)
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "players" WHERE "name" = \$1 AND "score" >= \$2$`).
+		WithArgs("john", "10").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "score"}))
+	s.mock.ExpectQuery(`^SELECT \* FROM "players" WHERE "score" >= \$1$`).
+		WithArgs("20").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "score"}))
+
+	var players []Player
+	err = s.db.Model(&Player{}).Scopes(prepared.Scope(newTestContext("name=john&score__gte=10"))).Find(&players).Error
+	s.NoError(err)
+	err = s.db.Model(&Player{}).Scopes(prepared.Scope(newTestContext("score__gte=20&password=secret"))).Find(&players).Error
+	s.NoError(err)
+}
+
+// TestPrepareInvalidKey checks that keys which aren't filterable are rejected.
+func (s *TestSuite) TestPrepareInvalidKey() {
+	_, err := Prepare(&User{}, Config{}, "password")
+	s.Error(err)
+	_, err = Prepare(&User{}, Config{}, "username__unknown")
+	s.Error(err)
+}
+
+// TestPreparedFilterList checks that the values of a prepared list filter
+// are split.
+func (s *TestSuite) TestPreparedFilterList() {
+	var members []Member
+	s.mock.ExpectQuery(`^SELECT \* FROM "members" WHERE "status" IN \(\$1,\$2\)$`).
+		WithArgs("a", "b").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "age", "role"}))
+	scope := MustPrepare(&Member{}, Config{}, "status__in").Bind(url.Values{"status__in": {"a,b"}})
+	err := s.db.Model(&Member{}).Scopes(scope).Find(&members).Error
+	s.NoError(err)
+}
+
+// TestPreparedFilterChecks checks that the unauthorized and invalid values
+// are ignored, or fail a strict scope.
+func (s *TestSuite) TestPreparedFilterChecks() {
+	config := Config{AuthorizeField: func(c *gin.Context, field string, capability int) bool {
+		return field != "role"
+	}}
+	values := url.Values{"role": {"admin"}, "age__gte": {"old"}, "status": {"active"}}
+
+	var members []Member
+	s.mock.ExpectQuery(`^SELECT \* FROM "members" WHERE "status" = \$1$`).
+		WithArgs("active").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "age", "role"}))
+	err := s.db.Model(&Member{}).Scopes(MustPrepare(&Member{}, config, "role", "age__gte", "status").Bind(values)).Find(&members).Error
+	s.NoError(err)
+
+	config.Strict = true
+	err = s.db.Model(&Member{}).Scopes(MustPrepare(&Member{}, config, "role", "age__gte", "status").Bind(values)).Find(&members).Error
+	s.EqualError(err, "filter: role: unauthorized filter")
+
+	config.CollectErrors = true
+	err = s.db.Model(&Member{}).Scopes(MustPrepare(&Member{}, config, "role", "age__gte", "status").Bind(values)).Find(&members).Error
+	s.ErrorContains(err, "filter: age__gte: invalid number")
+}
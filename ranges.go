@@ -0,0 +1,83 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ErrRangeNotSatisfiable is the error of the query when the requested Range
+// starts after the last row and Config.RangeNotSatisfiable is set. The
+// handler can answer it with a 416 status.
+var ErrRangeNotSatisfiable = errors.New("filter: range not satisfiable")
+
+// requestRange returns the first and last rows of the "Range: {unit}={first}-{last}"
+// header of the request, eg : "Range: items=0-24", if c is not nil.
+func requestRange(c *gin.Context, unit string) (int, int, bool) {
+	if unit == "" || c == nil {
+		return 0, 0, false
+	}
+	header := c.GetHeader("Range")
+	if header == "" {
+		return 0, 0, false
+	}
+	bounds, ok := strings.CutPrefix(header, unit+"=")
+	if !ok {
+		return 0, 0, false
+	}
+	firstValue, lastValue, ok := strings.Cut(bounds, "-")
+	if !ok {
+		return 0, 0, false
+	}
+	first, err := strconv.Atoi(firstValue)
+	if err != nil || first < 0 {
+		return 0, 0, false
+	}
+	last, err := strconv.Atoi(lastValue)
+	if err != nil || last < first {
+		return 0, 0, false
+	}
+	return first, last, true
+}
+
+// paginateRange limits db to the requested rows, 100 at most, and describes
+// them in the "Content-Range" header. A range starting after the last row is
+// moved to the last rows, or reported with ErrRangeNotSatisfiable if
+// config.RangeNotSatisfiable is set.
+func paginateRange(c *gin.Context, db *gorm.DB, count int64, first, last int, config Config) *gorm.DB {
+	limit := last - first + 1
+	if limit > 100 {
+		limit = 100
+	}
+	total := strconv.FormatInt(count, 10)
+	c.Header("Accept-Ranges", config.RangeUnit)
+	if int64(first) >= count && first > 0 {
+		if config.RangeNotSatisfiable {
+			c.Header("Content-Range", config.RangeUnit+" */"+total)
+			db.AddError(ErrRangeNotSatisfiable)
+			return db
+		}
+		first = int(count) - limit
+		if first < 0 {
+			first = 0
+		}
+	}
+	if count == 0 {
+		c.Header("Content-Range", config.RangeUnit+" */"+total)
+		return db.Limit(limit)
+	}
+	end := int64(first + limit)
+	if end > count {
+		end = count
+	}
+	c.Header("Content-Range", config.RangeUnit+" "+strconv.Itoa(first)+"-"+strconv.FormatInt(end-1, 10)+"/"+total)
+	return db.Offset(first).Limit(limit)
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+)
+
+func newRangeContext(w http.ResponseWriter, rangeHeader string) *gin.Context {
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = &http.Request{
+		URL:    &url.URL{},
+		Header: http.Header{"Range": []string{rangeHeader}},
+	}
+	return ctx
+}
+
+// TestPaginateRange checks the rows and the Content-Range of a satisfiable range.
+func (s *TestSuite) TestPaginateRange() {
+	var users []User
+	w := httptest.NewRecorder()
+	ctx := newRangeContext(w, "items=10-19")
+
+	s.mock.ExpectQuery(`^SELECT count\(\*\) FROM "users"`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(15))
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" ORDER BY "users"\."created_at" DESC LIMIT 10 OFFSET 10$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err := s.db.Model(&User{}).Scopes(FilterByConfig(ctx, Config{Flags: ALL, RangeUnit: "items"})).Find(&users).Error
+	s.NoError(err)
+	s.Equal("items 10-14/15", w.Header().Get("Content-Range"))
+}
+
+// TestPaginateRangeNotSatisfiable checks the out-of-range signal and the default clamping.
+func (s *TestSuite) TestPaginateRangeNotSatisfiable() {
+	var users []User
+	w := httptest.NewRecorder()
+	ctx := newRangeContext(w, "items=50-59")
+	config := Config{Flags: PAGINATE, RangeUnit: "items", RangeNotSatisfiable: true}
+
+	s.mock.ExpectQuery(`^SELECT count\(\*\) FROM "users"`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(15))
+	err := s.db.Model(&User{}).Scopes(FilterByConfig(ctx, config)).Find(&users).Error
+	s.ErrorIs(err, ErrRangeNotSatisfiable)
+	s.Equal("items */15", w.Header().Get("Content-Range"))
+
+	w = httptest.NewRecorder()
+	ctx = newRangeContext(w, "items=50-59")
+	config.RangeNotSatisfiable = false
+	s.mock.ExpectQuery(`^SELECT count\(\*\) FROM "users"`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(15))
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" LIMIT 10 OFFSET 5$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err = s.db.Model(&User{}).Scopes(FilterByConfig(ctx, config)).Find(&users).Error
+	s.NoError(err)
+	s.Equal("items 5-14/15", w.Header().Get("Content-Range"))
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"errors"
+	"regexp"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const regexOperator = "regex"
+
+func init() {
+	operators[regexOperator] = func(db *gorm.DB, _ Config, f Filter) clause.Expression {
+		if db.Dialector.Name() == "postgres" {
+			return clause.Expr{SQL: "? ~ ?", Vars: []interface{}{f.column(db), f.Value}}
+		}
+		return clause.Expr{SQL: "? REGEXP ?", Vars: []interface{}{f.column(db), f.Value}}
+	}
+}
+
+// validateRegex checks that the "regex" filters are allowed by config and
+// that value is a valid pattern, to not send the database a pattern it
+// fails on.
+func validateRegex(config Config, value string) error {
+	if !config.AllowRegex {
+		return errors.New("regex not allowed")
+	}
+	if _, err := regexp.Compile(value); err != nil {
+		return errors.New("invalid pattern")
+	}
+	return nil
+}
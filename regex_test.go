@@ -0,0 +1,43 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// TestFiltersRegex checks the regex operator on Postgres.
+func (s *TestSuite) TestFiltersRegex() {
+	var users []User
+	ctx := newTestContext("username__regex=%5Ejo.*n%24")
+	config := Config{Flags: FILTER, AllowRegex: true}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE "username" ~ \$1$`).
+		WithArgs("^jo.*n$").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err := s.db.Model(&User{}).Scopes(FilterByConfig(ctx, config)).Find(&users).Error
+	s.NoError(err)
+}
+
+// TestFiltersRegexRejected checks that the invalid patterns and the regex
+// filters not allowed by the config are ignored, or reported in strict mode.
+func (s *TestSuite) TestFiltersRegexRejected() {
+	var users []User
+	ctx := newTestContext("username__regex=%5Ejo(n")
+	config := Config{Flags: FILTER, AllowRegex: true}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "users"$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err := s.db.Model(&User{}).Scopes(FilterByConfig(ctx, config)).Find(&users).Error
+	s.NoError(err)
+
+	config.Strict = true
+	_, err = ParseQuery(ctx, &User{}, config)
+	s.EqualError(err, "filter: username__regex: invalid pattern")
+
+	_, err = ParseQuery(newTestContext("username__regex=jo"), &User{}, Config{Flags: FILTER, Strict: true})
+	s.EqualError(err, "filter: username__regex: regex not allowed")
+}
@@ -0,0 +1,276 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// relationByParam returns the has-one or has-many relation of s named param in
+// snake case, eg : "orders" for "Orders".
+func relationByParam(s *schema.Schema, param string) *schema.Relationship {
+	for _, relationship := range s.Relationships.Relations {
+		if relationship.Type != schema.HasMany && relationship.Type != schema.HasOne {
+			continue
+		}
+		if ToSnakeCase(relationship.Name) == param && len(relationship.References) == 1 {
+			return relationship
+		}
+	}
+	return nil
+}
+
+// relationCounts returns the columns selecting the number of rows of each
+// requested relation, eg : for "orders" on users
+//
+//	, (SELECT count(*) FROM "orders" WHERE "orders"."user_id" = "users"."id") AS "orders_count"
+//
+// Only the relations listed in allowed are counted.
+func relationCounts(s *schema.Schema, requested, allowed []string) (string, []interface{}) {
+	var (
+		sql  string
+		vars []interface{}
+	)
+	for _, param := range requested {
+		param = strings.TrimSpace(param)
+		if !contains(allowed, param) {
+			continue
+		}
+		relationship := relationByParam(s, param)
+		if relationship == nil {
+			continue
+		}
+		reference := relationship.References[0]
+		sql += ", (SELECT count(*) FROM ? WHERE ? = ?) AS ?"
+		vars = append(vars,
+			clause.Table{Name: relationship.FieldSchema.Table},
+			clause.Column{Table: relationship.FieldSchema.Table, Name: reference.ForeignKey.DBName},
+			clause.Column{Table: s.Table, Name: reference.PrimaryKey.DBName},
+			clause.Column{Name: param + "_count"},
+		)
+	}
+	return sql, vars
+}
+
+// countSuffix prefixes the operators of the relation count filters, eg :
+// "orders__count_gte=3".
+const countSuffix = "count_"
+
+// relationCountFilter returns the filter comparing the number of rows of a
+// relation listed in config.WithCount if key is a relation count filter, eg :
+// "orders__count_gte=3", along with an error if the count is invalid.
+func (config Config) relationCountFilter(key, value string, modelType reflect.Type) (Filter, bool, error) {
+	param, suffix, found := cutSuffix(key)
+	if !found || len(config.WithCount) == 0 || !contains(config.WithCount, param) {
+		return Filter{}, false, nil
+	}
+	operator, ok := strings.CutPrefix(suffix, countSuffix)
+	symbol, known := comparisonSymbols[operator]
+	if !ok || !known {
+		return Filter{}, false, nil
+	}
+	s, err := config.parseSchema(modelType)
+	if err != nil {
+		return Filter{}, false, nil
+	}
+	relationship := relationByParam(s, param)
+	if relationship == nil {
+		return Filter{}, false, nil
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		return Filter{}, true, errors.New("invalid count")
+	}
+
+	reference := relationship.References[0]
+	if config.HavingCount {
+		relationTable := relationship.FieldSchema.Table
+		foreignKey := clause.Column{Table: relationTable, Name: reference.ForeignKey.DBName}
+		primaryKey := clause.Column{Table: s.Table, Name: reference.PrimaryKey.DBName}
+		counted := interface{}(foreignKey)
+		if field := relationship.FieldSchema.PrioritizedPrimaryField; field != nil {
+			counted = clause.Column{Table: relationTable, Name: field.DBName}
+		}
+		return Filter{
+			Param:    param,
+			Operator: suffix,
+			Value:    value,
+			having: &havingCount{
+				join:    clause.Expr{SQL: "LEFT JOIN ? ON ? = ?", Vars: []interface{}{clause.Table{Name: relationTable}, foreignKey, primaryKey}},
+				groupBy: primaryKey,
+				having:  clause.Expr{SQL: "COUNT(DISTINCT ?) " + symbol + " ?", Vars: []interface{}{counted, count}},
+			},
+		}, true, nil
+	}
+	return Filter{
+		Param:    param,
+		Operator: suffix,
+		Value:    value,
+		expr: clause.Expr{
+			SQL: "(SELECT count(*) FROM ? WHERE ? = ?) " + symbol + " ?",
+			Vars: []interface{}{
+				clause.Table{Name: relationship.FieldSchema.Table},
+				clause.Column{Table: relationship.FieldSchema.Table, Name: reference.ForeignKey.DBName},
+				clause.Column{Table: s.Table, Name: reference.PrimaryKey.DBName},
+				count,
+			},
+		},
+	}, true, nil
+}
+
+// havingCount is a relation count filter applied with Config.HavingCount: the
+// relation is joined, the rows are grouped by primary key and the count is
+// compared in the HAVING clause.
+type havingCount struct {
+	join    clause.Expr
+	groupBy clause.Column
+	having  clause.Expr
+}
+
+// groupByHavingCounts applies the relation count filters of filters applied
+// with Config.HavingCount, each relation being joined once.
+func groupByHavingCounts(db *gorm.DB, filters []Filter) *gorm.DB {
+	joined := map[string]bool{}
+	for _, f := range filters {
+		if f.having == nil {
+			continue
+		}
+		if len(joined) == 0 {
+			db = db.Clauses(clause.GroupBy{Columns: []clause.Column{f.having.groupBy}})
+		}
+		if !joined[f.Param] {
+			db = db.Joins(f.having.join.SQL, f.having.join.Vars...)
+			joined[f.Param] = true
+		}
+		db = db.Having(f.having.having.SQL, f.having.having.Vars...)
+	}
+	return db
+}
+
+// manyToManyFilter returns the filter of a field of a many-to-many relation
+// listed in config.ManyToMany if key is prefixed by the relation, eg :
+// "roles.name=admin", along with an error if the value is invalid. values are
+// the values of key, bound at once by the list operators.
+func (config Config) manyToManyFilter(c *gin.Context, key, value string, values []string, modelType reflect.Type) (Filter, bool, error) {
+	relation, _, found := strings.Cut(key, ".")
+	if !found || !contains(config.ManyToMany, relation) {
+		return Filter{}, false, nil
+	}
+	s, err := config.parseSchema(modelType)
+	if err != nil {
+		return Filter{}, false, nil
+	}
+	relationship := manyToManyByParam(s, relation)
+	if relationship == nil {
+		return Filter{}, false, nil
+	}
+	key, value, separator := getSeparator(strings.TrimPrefix(key, relation+"."), value)
+	matched := matchFilters(key, separator, relationship.FieldSchema.ModelType, config)
+	if len(matched) == 0 {
+		return Filter{}, true, errors.New("unknown filter")
+	}
+
+	filter := matched[0]
+	var ok bool
+	if listOperators[filter.Operator] {
+		ok, err = filter.bindList(c, splitList(values), config)
+	} else {
+		ok, err = filter.bind(c, value, config)
+	}
+	if err != nil {
+		return Filter{}, true, err
+	}
+	if !ok {
+		// The "any" sentinel matches every row, without join.
+		return Filter{Param: relation + "." + filter.Param}, true, nil
+	}
+	joinTable := relationship.JoinTable.Table
+	var ownerJoin, relationJoin []interface{}
+	for _, reference := range relationship.References {
+		if reference.OwnPrimaryKey {
+			ownerJoin = append(ownerJoin, clause.Column{Table: joinTable, Name: reference.ForeignKey.DBName}, clause.Column{Table: s.Table, Name: reference.PrimaryKey.DBName})
+		} else {
+			relationJoin = append(relationJoin, clause.Column{Table: relationship.FieldSchema.Table, Name: reference.PrimaryKey.DBName}, clause.Column{Table: joinTable, Name: reference.ForeignKey.DBName})
+		}
+	}
+	filter.Param = relation + "." + filter.Param
+	filter.table = relationship.FieldSchema.Table
+	filter.joins = []clause.Expr{
+		{SQL: "JOIN ? ON " + joinConditions(len(ownerJoin)/2), Vars: append([]interface{}{clause.Table{Name: joinTable}}, ownerJoin...)},
+		{SQL: "JOIN ? ON " + joinConditions(len(relationJoin)/2), Vars: append([]interface{}{clause.Table{Name: relationship.FieldSchema.Table}}, relationJoin...)},
+	}
+	return filter, true, nil
+}
+
+// manyToManyByParam returns the many-to-many relation of s named param in
+// snake case, eg : "roles" for "Roles".
+func manyToManyByParam(s *schema.Schema, param string) *schema.Relationship {
+	for _, relationship := range s.Relationships.Relations {
+		if relationship.Type == schema.Many2Many && relationship.JoinTable != nil && ToSnakeCase(relationship.Name) == param {
+			return relationship
+		}
+	}
+	return nil
+}
+
+// joinConditions returns the SQL of n equalities of columns joined by AND.
+func joinConditions(n int) string {
+	conditions := make([]string, n)
+	for i := range conditions {
+		conditions[i] = "? = ?"
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// joinManyToMany joins the join tables and the relations of the many-to-many
+// relation filters of filters, each relation being joined once.
+func joinManyToMany(db *gorm.DB, filters []Filter) *gorm.DB {
+	joined := map[string]bool{}
+	for _, f := range filters {
+		if len(f.joins) == 0 {
+			continue
+		}
+		relation, _, _ := strings.Cut(f.Param, ".")
+		if joined[relation] {
+			continue
+		}
+		for _, join := range f.joins {
+			db = db.Joins(join.SQL, join.Vars...)
+		}
+		joined[relation] = true
+	}
+	return db
+}
+
+// joinsManyToMany reports whether filters join a many-to-many relation.
+func joinsManyToMany(filters []Filter) bool {
+	for _, f := range filters {
+		if len(f.joins) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// joinsRelations reports whether filters join a relation, whose columns could
+// make the columns of the model ambiguous: a many-to-many relation, or a
+// relation counted with Config.HavingCount.
+func joinsRelations(filters []Filter) bool {
+	for _, f := range filters {
+		if len(f.joins) > 0 || f.having != nil {
+			return true
+		}
+	}
+	return false
+}
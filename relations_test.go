@@ -0,0 +1,163 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/gorm/schema"
+)
+
+type Customer struct {
+	Id        int64
+	Name      string `filter:"filterable"`
+	Orders    []Order
+	Addresses []Address
+}
+
+type Order struct {
+	Id         int64
+	CustomerID int64
+	Amount     float64
+}
+
+type Address struct {
+	Id         int64
+	CustomerID int64
+}
+
+// TestWithCount checks the correlated count subselect of an allowed relation.
+func (s *TestSuite) TestWithCount() {
+	var customers []Customer
+	ctx := newTestContext("with_count=orders,addresses&name=john")
+
+	s.mock.ExpectQuery(`^SELECT "customers"\.\*, \(SELECT count\(\*\) FROM "orders" WHERE "orders"\."customer_id" = "customers"\."id"\) AS "orders_count" FROM "customers" WHERE "name" = \$1$`).
+		WithArgs("john").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "orders_count"}))
+	err := s.db.Model(&Customer{}).Scopes(FilterByConfig(ctx, Config{Flags: FILTER, WithCount: []string{"orders"}})).Find(&customers).Error
+	s.NoError(err)
+}
+
+// TestFiltersRelationCount checks the comparison of the number of rows of a relation.
+func (s *TestSuite) TestFiltersRelationCount() {
+	var customers []Customer
+	ctx := newTestContext("orders__count_gte=3&addresses__count_eq=0")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "customers" WHERE \(SELECT count\(\*\) FROM "orders" WHERE "orders"\."customer_id" = "customers"\."id"\) >= \$1$`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	err := s.db.Model(&Customer{}).Scopes(FilterByConfig(ctx, Config{Flags: FILTER, WithCount: []string{"orders"}})).Find(&customers).Error
+	s.NoError(err)
+
+	_, err = ParseQuery(newTestContext("orders__count_gte=many"), &Customer{}, Config{Flags: FILTER, WithCount: []string{"orders"}, Strict: true})
+	s.EqualError(err, "filter: orders__count_gte: invalid count")
+}
+
+// TestFiltersHavingCount checks that the relation count filters can group by
+// primary key with a HAVING clause, the count included.
+func (s *TestSuite) TestFiltersHavingCount() {
+	var customers []Customer
+	ctx := newTestContext("orders__count_gt=5&name=john")
+	config := Config{Flags: FILTER | PAGINATE, WithCount: []string{"orders"}, HavingCount: true}
+
+	s.mock.ExpectQuery(`^SELECT count\(\*\) FROM "customers" LEFT JOIN "orders" ON "orders"\."customer_id" = "customers"\."id" WHERE "customers"\."name" = \$1 GROUP BY "customers"\."id" HAVING COUNT\(DISTINCT "orders"\."id"\) > \$2$`).
+		WithArgs("john", 5).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1).AddRow(1))
+	s.mock.ExpectQuery(`^SELECT "customers"\."id","customers"\."name" FROM "customers" LEFT JOIN "orders" ON "orders"\."customer_id" = "customers"\."id" WHERE "customers"\."name" = \$1 GROUP BY "customers"\."id" HAVING COUNT\(DISTINCT "orders"\."id"\) > \$2 LIMIT 20$`).
+		WithArgs("john", 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	err := s.db.Model(&Customer{}).Scopes(FilterByConfig(ctx, config)).Find(&customers).Error
+	s.NoError(err)
+}
+
+type Vendor struct {
+	Id     int64
+	Name   string `filter:"filterable;searchable"`
+	Stocks []Stock
+}
+
+type Stock struct {
+	Id       int64
+	VendorID int64
+	Name     string
+}
+
+// TestFiltersHavingCountAmbiguous checks that the filtered and searched
+// columns are qualified by the table of the model when a counted relation
+// is joined.
+func (s *TestSuite) TestFiltersHavingCountAmbiguous() {
+	var vendors []Vendor
+	ctx := newTestContext("name=acme&search=x&stocks__count_gt=1")
+	config := Config{Flags: FILTER | SEARCH, WithCount: []string{"stocks"}, HavingCount: true}
+
+	s.mock.ExpectQuery(`^SELECT "vendors"\."id","vendors"\."name" FROM "vendors" LEFT JOIN "stocks" ON "stocks"\."vendor_id" = "vendors"\."id" WHERE "vendors"\."name" = \$1 AND "vendors"\."name" LIKE \$2 GROUP BY "vendors"\."id" HAVING COUNT\(DISTINCT "stocks"\."id"\) > \$3$`).
+		WithArgs("acme", "%x%", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	err := s.db.Model(&Vendor{}).Scopes(FilterByConfig(ctx, config)).Find(&vendors).Error
+	s.NoError(err)
+}
+
+type Staff struct {
+	Id    int64
+	Name  string `filter:"filterable;searchable"`
+	Roles []Role `gorm:"many2many:staff_roles"`
+}
+
+// TestFiltersManyToMany checks that a many-to-many relation filter joins the
+// join table and the relation, the rows being selected distinct.
+func (s *TestSuite) TestFiltersManyToMany() {
+	var staffs []Staff
+	ctx := newTestContext("roles.name=admin&name=john")
+
+	s.mock.ExpectQuery(`^SELECT DISTINCT "staffs"\.\* FROM "staffs" JOIN "staff_roles" ON "staff_roles"\."staff_id" = "staffs"\."id" JOIN "roles" ON "roles"\."id" = "staff_roles"\."role_id" WHERE "staffs"\."name" = \$1 AND "roles"\."name" = \$2$`).
+		WithArgs("john", "admin").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	err := s.db.Model(&Staff{}).Scopes(FilterByConfig(ctx, Config{Flags: FILTER, ManyToMany: []string{"roles"}})).Find(&staffs).Error
+	s.NoError(err)
+
+	_, err = ParseQuery(newTestContext("roles.label=admin"), &Staff{}, Config{Flags: FILTER, ManyToMany: []string{"roles"}, Strict: true})
+	s.EqualError(err, "filter: roles.label: unknown filter")
+}
+
+// TestFiltersManyToManySearch checks that the searched columns are qualified
+// by the table of the model when a many-to-many relation is joined.
+func (s *TestSuite) TestFiltersManyToManySearch() {
+	var staffs []Staff
+	ctx := newTestContext("roles.name=admin&search=42")
+	config := Config{Flags: FILTER | SEARCH, ManyToMany: []string{"roles"}, SearchPrimaryKey: true}
+
+	s.mock.ExpectQuery(`^SELECT DISTINCT "staffs"\.\* FROM "staffs" JOIN "staff_roles" ON "staff_roles"\."staff_id" = "staffs"\."id" JOIN "roles" ON "roles"\."id" = "staff_roles"\."role_id" WHERE "roles"\."name" = \$1 AND \("staffs"\."name" LIKE \$2 OR "staffs"\."id" = \$3\)$`).
+		WithArgs("admin", "%42%", int64(42)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	err := s.db.Model(&Staff{}).Scopes(FilterByConfig(ctx, config)).Find(&staffs).Error
+	s.NoError(err)
+
+	s.mock.ExpectQuery(`^SELECT DISTINCT "staffs"\.\* FROM "staffs" JOIN "staff_roles" .* WHERE "roles"\."name" = \$1 AND to_tsvector\(concat_ws\(' ', "staffs"\."name"\)\) @@ plainto_tsquery\(\$2\)$`).
+		WithArgs("admin", "42").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	config = Config{Flags: FILTER | SEARCH, ManyToMany: []string{"roles"}, FullTextSearch: "plain"}
+	err = s.db.Model(&Staff{}).Scopes(FilterByConfig(newTestContext("roles.name=admin&search=42"), config)).Find(&staffs).Error
+	s.NoError(err)
+}
+
+// TestFiltersRelationsNamingStrategy checks that the tables of the relation
+// filters follow the naming strategy of the database.
+func (s *TestSuite) TestFiltersRelationsNamingStrategy() {
+	s.db.Config.NamingStrategy = schema.NamingStrategy{TablePrefix: "app_"}
+
+	var customers []Customer
+	s.mock.ExpectQuery(`^SELECT \* FROM "app_customers" WHERE \(SELECT count\(\*\) FROM "app_orders" WHERE "app_orders"\."customer_id" = "app_customers"\."id"\) >= \$1$`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	err := s.db.Model(&Customer{}).Scopes(FilterByConfig(newTestContext("orders__count_gte=3"), Config{Flags: FILTER, WithCount: []string{"orders"}})).Find(&customers).Error
+	s.NoError(err)
+
+	var staffs []Staff
+	s.mock.ExpectQuery(`^SELECT DISTINCT "app_staffs"\.\* FROM "app_staffs" JOIN "app_staff_roles" ON "app_staff_roles"\."staff_id" = "app_staffs"\."id" JOIN "app_roles" ON "app_roles"\."id" = "app_staff_roles"\."role_id" WHERE "app_roles"\."name" = \$1$`).
+		WithArgs("admin").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	err = s.db.Model(&Staff{}).Scopes(FilterByConfig(newTestContext("roles.name=admin"), Config{Flags: FILTER, ManyToMany: []string{"roles"}})).Find(&staffs).Error
+	s.NoError(err)
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// searchColumns returns the columns of the `searchable` fields of modelType.
+// If config.SearchFields is not empty, the columns not listed in it are left
+// out, and so are the fields not tagged `indexed` if config.IndexedSearchOnly
+// is set. At most config.MaxSearchFields columns are kept, the fields with
+// the lowest `priority` option first, then the others in their order.
+func searchColumns(modelType reflect.Type, config Config) []string {
+	var (
+		columns    []string
+		priorities []int
+	)
+	for i := 0; i < modelType.NumField(); i++ {
+		field := modelType.Field(i)
+		if !strings.Contains(field.Tag.Get(tagKey), "searchable") {
+			continue
+		}
+		if config.IndexedSearchOnly && !hasTagFlag(field, "indexed") {
+			continue
+		}
+		column := getColumnNameForField(field)
+		if len(config.SearchFields) > 0 && !contains(config.SearchFields, column) {
+			continue
+		}
+		columns = append(columns, column)
+		priority := math.MaxInt
+		if option, ok := tagOption(field, "priority"); ok {
+			if p, err := strconv.Atoi(option); err == nil {
+				priority = p
+			}
+		}
+		priorities = append(priorities, priority)
+	}
+	if config.MaxSearchFields > 0 && len(columns) > config.MaxSearchFields {
+		indexes := make([]int, len(columns))
+		for i := range indexes {
+			indexes[i] = i
+		}
+		sort.SliceStable(indexes, func(i, j int) bool {
+			return priorities[indexes[i]] < priorities[indexes[j]]
+		})
+		kept := make([]string, 0, config.MaxSearchFields)
+		for _, i := range indexes[:config.MaxSearchFields] {
+			kept = append(kept, columns[i])
+		}
+		columns = kept
+	}
+	return columns
+}
+
+// expressionBySearch matches search against columns, or key, the primary key
+// equality of the search, if not nil. The columns are qualified by the table
+// of the model if qualified is set, eg : when a relation is joined.
+func expressionBySearch(db *gorm.DB, search string, columns []string, key clause.Expression, qualified bool, config Config) *gorm.DB {
+	if search == "" || len(columns) == 0 && key == nil {
+		return db
+	}
+	table := ""
+	if qualified {
+		table = clause.CurrentTable
+		if eq, ok := key.(clause.Eq); ok {
+			if column, ok := eq.Column.(clause.Column); ok {
+				column.Table = table
+				eq.Column = column
+				key = eq
+			}
+		}
+	}
+	expressions := make([]clause.Expression, 0, len(columns)+1)
+	if config.FullTextSearch != "" && db.Dialector.Name() == "postgres" && len(columns) > 0 {
+		expressions = append(expressions, fullTextSearch(config.FullTextSearch, search, columns, table))
+	} else {
+		unaccent := config.UnaccentSearch && db.Dialector.Name() == "postgres"
+		if config.UnaccentSearch && !unaccent {
+			search = removeAccents(search)
+		}
+		pattern := containsPattern(search)
+		for _, column := range columns {
+			if unaccent {
+				expressions = append(expressions, clause.Expr{
+					SQL:  "unaccent(?) LIKE unaccent(?)",
+					Vars: []interface{}{clause.Column{Table: table, Name: column}, pattern},
+				})
+			} else {
+				expressions = append(expressions, clause.Like{Column: clause.Column{Table: table, Name: column}, Value: pattern})
+			}
+		}
+	}
+	if key != nil {
+		expressions = append(expressions, key)
+	}
+	return db.Where(joinOr(expressions))
+}
+
+// impliedSearch reports whether the search of q is left out with
+// Config.SkipImpliedSearch: an equality filter on a searched column holds the
+// search, so the rows it matches match the search too.
+func (q *ParsedQuery) impliedSearch() bool {
+	if !q.Config.SkipImpliedSearch || q.Config.FullTextSearch != "" || q.Params.Search == "" {
+		return false
+	}
+	for _, f := range q.Filters {
+		if f.Operator == "eq" && !f.OrNull && len(f.jsonPath) == 0 && f.table == "" &&
+			contains(q.SearchColumns, f.Column) && strings.Contains(f.Value, q.Params.Search) {
+			return true
+		}
+	}
+	return false
+}
+
+// primaryKeySearch returns the equality of the integer primary key of
+// modelType with search, or nil if search is not a key.
+func (config Config) primaryKeySearch(modelType reflect.Type, search string) clause.Expression {
+	name := config.primaryKeyField(modelType)
+	if name == "" {
+		return nil
+	}
+	field, ok := modelType.FieldByName(name)
+	if !ok || !isInteger(field.Type) {
+		return nil
+	}
+	key, err := coerceKey(field.Type, strings.TrimSpace(search))
+	if err != nil {
+		return nil
+	}
+	return clause.Eq{Column: clause.Column{Name: getColumnNameForField(field)}, Value: key}
+}
+
+// tsqueryFunctions are the functions turning the search into a tsquery, by
+// Config.FullTextSearch mode.
+var tsqueryFunctions = map[string]string{
+	"plain":     "plainto_tsquery",
+	"websearch": "websearch_to_tsquery",
+	"tsquery":   "to_tsquery",
+}
+
+// fullTextSearch matches the rows whose columns hold the words of search, eg :
+// to_tsvector(concat_ws(' ', "title", "body")) @@ plainto_tsquery($1). The
+// "tsquery" mode keeps the words of search only, joined with "&", as to_tsquery
+// fails on its operators, eg : "go & | rust:" becomes "go & rust". The
+// columns are qualified by table if not empty.
+func fullTextSearch(mode, search string, columns []string, table string) clause.Expression {
+	function, ok := tsqueryFunctions[mode]
+	if !ok {
+		function = tsqueryFunctions["plain"]
+	}
+	if function == "to_tsquery" {
+		search = tsqueryWords(search)
+	}
+	vars := make([]interface{}, 0, len(columns)+1)
+	for _, column := range columns {
+		vars = append(vars, clause.Column{Table: table, Name: column})
+	}
+	return clause.Expr{
+		SQL:  "to_tsvector(concat_ws(' '" + strings.Repeat(", ?", len(columns)) + ")) @@ " + function + "(?)",
+		Vars: append(vars, search),
+	}
+}
+
+// tsqueryWords returns the words of search joined with "&", without the
+// tsquery operators.
+func tsqueryWords(search string) string {
+	words := strings.FieldsFunc(search, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " & ")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
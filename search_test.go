@@ -0,0 +1,205 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"database/sql/driver"
+	"net/url"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// TestSearchOverrideFields checks that the search can be restricted at call time.
+func (s *TestSuite) TestSearchOverrideFields() {
+	var users []User
+	ctx := newTestContext("search=John")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE "full_name" LIKE \$1$`).
+		WithArgs("%John%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "Username", "FullName", "Email", "Password"}))
+	err := s.db.Model(&User{}).Scopes(FilterByConfig(ctx, Config{
+		Flags:        SEARCH,
+		SearchFields: []string{"full_name", "password"},
+	})).Find(&users).Error
+	s.NoError(err)
+}
+
+type Keyword struct {
+	Id     int64
+	Search string `filter:"filterable"`
+	Label  string `filter:"searchable"`
+}
+
+// TestFiltersReclaimedSearchParam checks that a reserved param can filter a field.
+func (s *TestSuite) TestFiltersReclaimedSearchParam() {
+	var keywords []Keyword
+	ctx := newTestContext("search=golang")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "keywords" WHERE "search" = \$1$`).
+		WithArgs("golang").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "search", "label"}))
+	err := s.db.Model(&Keyword{}).Scopes(FilterByConfig(ctx, Config{
+		Flags:                SEARCH | FILTER,
+		FilterReservedParams: []string{"search"},
+	})).Find(&keywords).Error
+	s.NoError(err)
+}
+
+// TestFiltersReservedSearchParam checks that search stays reserved by default.
+func (s *TestSuite) TestFiltersReservedSearchParam() {
+	var keywords []Keyword
+	ctx := newTestContext("search=golang")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "keywords" WHERE "label" LIKE \$1$`).
+		WithArgs("%golang%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "search", "label"}))
+	err := s.db.Model(&Keyword{}).Scopes(FilterByQuery(ctx, SEARCH|FILTER)).Find(&keywords).Error
+	s.NoError(err)
+}
+
+type Note struct {
+	Id    int64
+	Title string `filter:"searchable;indexed"`
+	Body  string `filter:"searchable"`
+}
+
+// TestSearchIndexedOnly checks that the unindexed fields are not searched.
+func (s *TestSuite) TestSearchIndexedOnly() {
+	var notes []Note
+	ctx := newTestContext("search=golang")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "notes" WHERE "title" LIKE \$1$`).
+		WithArgs("%golang%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "body"}))
+	err := s.db.Model(&Note{}).Scopes(FilterByConfig(ctx, Config{Flags: SEARCH, IndexedSearchOnly: true})).Find(&notes).Error
+	s.NoError(err)
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "notes" WHERE \("title" LIKE \$1 OR "body" LIKE \$2\)$`).
+		WithArgs("%golang%", "%golang%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "body"}))
+	err = s.db.Model(&Note{}).Scopes(FilterByQuery(ctx, SEARCH)).Find(&notes).Error
+	s.NoError(err)
+}
+
+type Speaker struct {
+	Id   int64
+	Name string `gorm:"column:display_name" filter:"param:name;searchable;filterable"`
+}
+
+// TestSearchParamAlias checks that the param of a field renames neither its
+// filtered nor its searched column.
+func (s *TestSuite) TestSearchParamAlias() {
+	var speakers []Speaker
+	ctx := newTestContext("name=Ada&search=love")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "speakers" WHERE "display_name" = \$1 AND "display_name" LIKE \$2$`).
+		WithArgs("Ada", "%love%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "display_name"}))
+	err := s.db.Model(&Speaker{}).Scopes(FilterByQuery(ctx, SEARCH|FILTER)).Find(&speakers).Error
+	s.NoError(err)
+
+	query, err := ParseQuery(newTestContext("display_name=Ada"), &Speaker{}, Config{Flags: FILTER})
+	s.NoError(err)
+	s.Empty(query.Filters)
+}
+
+type Listing struct {
+	Id          int64
+	Description string `filter:"searchable"`
+	Title       string `filter:"searchable;priority:1"`
+	City        string `filter:"searchable"`
+	Sku         string `filter:"searchable;priority:2"`
+}
+
+// TestSearchMaxFields checks that only the fields of highest priority are
+// searched.
+func (s *TestSuite) TestSearchMaxFields() {
+	var listings []Listing
+	ctx := newTestContext("search=loft")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "listings" WHERE \("title" LIKE \$1 OR "sku" LIKE \$2 OR "description" LIKE \$3\)$`).
+		WithArgs("%loft%", "%loft%", "%loft%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "description", "title", "city", "sku"}))
+	err := s.db.Model(&Listing{}).Scopes(FilterByConfig(ctx, Config{Flags: SEARCH, MaxSearchFields: 3})).Find(&listings).Error
+	s.NoError(err)
+}
+
+// TestSearchFullText checks that the search is a safe tsquery whatever its
+// characters.
+func (s *TestSuite) TestSearchFullText() {
+	var notes []Note
+	for mode, expected := range map[string][]string{
+		"plain":     {"plainto_tsquery", "go & | rust:*"},
+		"websearch": {"websearch_to_tsquery", "go & | rust:*"},
+		"tsquery":   {"to_tsquery", "go & rust"},
+	} {
+		ctx := newTestContext("search=" + url.QueryEscape("go & | rust:*"))
+
+		s.mock.ExpectQuery(`^SELECT \* FROM "notes" WHERE to_tsvector\(concat_ws\(' ', "title", "body"\)\) @@ ` + expected[0] + `\(\$1\)$`).
+			WithArgs(expected[1]).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "body"}))
+		err := s.db.Model(&Note{}).Scopes(FilterByConfig(ctx, Config{Flags: SEARCH, FullTextSearch: mode})).Find(&notes).Error
+		s.NoError(err, mode)
+	}
+}
+
+// TestFiltersSearchPrimaryKey checks that a numeric search also matches the
+// primary key, and that a text search doesn't.
+func (s *TestSuite) TestFiltersSearchPrimaryKey() {
+	var users []User
+	config := Config{Flags: SEARCH, SearchPrimaryKey: true}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE \("username" LIKE \$1 OR "full_name" LIKE \$2 OR "id" = \$3\)$`).
+		WithArgs("%42%", "%42%", int64(42)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username"}))
+	err := s.db.Model(&User{}).Scopes(FilterByConfig(newTestContext("search=42"), config)).Find(&users).Error
+	s.NoError(err)
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE \("username" LIKE \$1 OR "full_name" LIKE \$2\)$`).
+		WithArgs("%John%", "%John%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username"}))
+	err = s.db.Model(&User{}).Scopes(FilterByConfig(newTestContext("search=John"), config)).Find(&users).Error
+	s.NoError(err)
+}
+
+// TestFiltersUnaccentSearch checks that both sides of the search are wrapped
+// in unaccent() on Postgres.
+func (s *TestSuite) TestFiltersUnaccentSearch() {
+	var users []User
+	ctx := newTestContext("search=jose")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE \(unaccent\("username"\) LIKE unaccent\(\$1\) OR unaccent\("full_name"\) LIKE unaccent\(\$2\)\)$`).
+		WithArgs("%jose%", "%jose%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username"}))
+	err := s.db.Model(&User{}).Scopes(FilterByConfig(ctx, Config{Flags: SEARCH, UnaccentSearch: true})).Find(&users).Error
+	s.NoError(err)
+
+	s.Equal("Jose Muller", removeAccents("José Müller"))
+}
+
+// TestFiltersFilteredAndSearchedField checks that a filter and a search on
+// the same column are ANDed, and that the search implied by the filter is
+// left out with SkipImpliedSearch.
+func (s *TestSuite) TestFiltersFilteredAndSearchedField() {
+	var users []User
+	for _, expected := range []struct {
+		query string
+		skip  bool
+		sql   string
+		args  []driver.Value
+	}{
+		{"username=john&search=john", false, `WHERE "username" = \$1 AND \("username" LIKE \$2 OR "full_name" LIKE \$3\)`, []driver.Value{"john", "%john%", "%john%"}},
+		{"username=john&search=john", true, `WHERE "username" = \$1`, []driver.Value{"john"}},
+		{"username=john&search=doe", true, `WHERE "username" = \$1 AND \("username" LIKE \$2 OR "full_name" LIKE \$3\)`, []driver.Value{"john", "%doe%", "%doe%"}},
+	} {
+		s.mock.ExpectQuery(`^SELECT \* FROM "users" ` + expected.sql + `$`).
+			WithArgs(expected.args...).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "username"}))
+		config := Config{Flags: FILTER | SEARCH, SkipImpliedSearch: expected.skip}
+		err := s.db.Model(&User{}).Scopes(FilterByConfig(newTestContext(expected.query), config)).Find(&users).Error
+		s.NoError(err, expected.query)
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"reflect"
+	"strconv"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const isNull = "isnull"
+
+var deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+
+func init() {
+	operators[isNull] = func(db *gorm.DB, _ Config, f Filter) clause.Expression {
+		if null, _ := strconv.ParseBool(f.Value); null {
+			return clause.Expr{SQL: "? IS NULL", Vars: []interface{}{f.column(db)}}
+		}
+		return clause.Expr{SQL: "? IS NOT NULL", Vars: []interface{}{f.column(db)}}
+	}
+}
+
+// isSoftDelete reports whether t is the soft delete field of GORM.
+func isSoftDelete(t reflect.Type) bool {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t == deletedAtType
+}
+
+// unscopes reports whether filters filter on the soft delete column, eg :
+// "deleted_at__isnull=false", which GORM would hide the rows of.
+func unscopes(filters []Filter) bool {
+	for _, f := range filters {
+		if f.softDelete {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/gorm"
+)
+
+type Archive struct {
+	Id        int64
+	Title     string         `filter:"filterable"`
+	DeletedAt gorm.DeletedAt `filter:"filterable"`
+}
+
+// TestFiltersSoftDelete checks that a filter on the soft delete column
+// unscopes the query, the count included.
+func (s *TestSuite) TestFiltersSoftDelete() {
+	var archives []Archive
+	ctx := newTestContext("deleted_at__isnull=false&title=report")
+
+	s.mock.ExpectQuery(`^SELECT count\(\*\) FROM "archives" WHERE "deleted_at" IS NOT NULL AND "title" = \$1$`).
+		WithArgs("report").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectQuery(`^SELECT \* FROM "archives" WHERE "deleted_at" IS NOT NULL AND "title" = \$1 ORDER BY "archives"."created_at" DESC LIMIT 20$`).
+		WithArgs("report").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "deleted_at"}))
+	err := s.db.Model(&Archive{}).Scopes(FilterByQuery(ctx, ALL)).Find(&archives).Error
+	s.NoError(err)
+}
+
+// TestFiltersSoftDeleteScoped checks that the other filters keep the soft
+// deleted rows hidden, and that isnull expects a boolean.
+func (s *TestSuite) TestFiltersSoftDeleteScoped() {
+	var archives []Archive
+	ctx := newTestContext("title__isnull=true&deleted_at__isnull=maybe")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "archives" WHERE "title" IS NULL AND "archives"."deleted_at" IS NULL$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "deleted_at"}))
+	err := s.db.Model(&Archive{}).Scopes(FilterByQuery(ctx, FILTER)).Find(&archives).Error
+	s.NoError(err)
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// boundLimit is a LIMIT clause binding the limit and the offset as vars, eg :
+// LIMIT $1 OFFSET $2, instead of the literals of clause.Limit, so that every
+// page runs the same statement.
+type boundLimit struct {
+	clause.Limit
+}
+
+func (limit boundLimit) Build(builder clause.Builder) {
+	if limit.Limit.Limit != nil && *limit.Limit.Limit >= 0 {
+		builder.WriteString("LIMIT ")
+		builder.AddVar(builder, *limit.Limit.Limit)
+	}
+	if limit.Offset > 0 {
+		if limit.Limit.Limit != nil && *limit.Limit.Limit >= 0 {
+			builder.WriteByte(' ')
+		}
+		builder.WriteString("OFFSET ")
+		builder.AddVar(builder, limit.Offset)
+	}
+}
+
+func (limit boundLimit) MergeClause(c *clause.Clause) {
+	c.Name = ""
+	c.Expression = limit
+}
+
+// bindLimit replaces the LIMIT clause of db by a boundLimit.
+func bindLimit(db *gorm.DB) *gorm.DB {
+	c, ok := db.Statement.Clauses["LIMIT"]
+	if !ok {
+		return db
+	}
+	if limit, ok := c.Expression.(clause.Limit); ok {
+		c.Expression = boundLimit{limit}
+		db.Statement.Clauses["LIMIT"] = c
+	}
+	return db
+}
+
+// listLimit is the LIMIT clause of a paginated list. The rows are counted and
+// the pagination headers written when the clause is built by a SELECT, so the
+// statements which don't fetch rows, eg : db.Delete(&users), neither count
+// nor paginate, even with MySQL building the LIMIT of a DELETE.
+type listLimit struct {
+	// paginate paginates the new session db, once the rows are counted.
+	paginate func(db *gorm.DB) *gorm.DB
+	built    bool
+	limit    clause.Expression
+}
+
+func (limit *listLimit) Build(builder clause.Builder) {
+	stmt, ok := builder.(*gorm.Statement)
+	if !ok || len(stmt.BuildClauses) == 0 || stmt.BuildClauses[0] != "SELECT" {
+		return
+	}
+	if !limit.built {
+		limit.built = true
+		db := limit.paginate(stmt.DB.Session(&gorm.Session{NewDB: true}))
+		if db.Error != nil {
+			stmt.DB.AddError(db.Error)
+			return
+		}
+		limit.limit = db.Statement.Clauses["LIMIT"].Expression
+	}
+	if limit.limit != nil {
+		limit.limit.Build(builder)
+	}
+}
+
+func (limit *listLimit) MergeClause(c *clause.Clause) {
+	c.Name = ""
+	c.Expression = limit
+}
+
+func (limit *listLimit) Name() string {
+	return "LIMIT"
+}
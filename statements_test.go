@@ -0,0 +1,33 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"regexp"
+
+	"gorm.io/gorm"
+)
+
+// TestFiltersStableStatements checks that no value of the request is
+// interpolated in the SQL, which is the same for any number of values.
+func (s *TestSuite) TestFiltersStableStatements() {
+	config := Config{Flags: ALL, StableStatements: true}
+	query := func(values string) string {
+		var products []Product
+		ctx := newTestContext(values)
+		stmt := s.db.Session(&gorm.Session{DryRun: true}).Model(&Product{}).
+			Scopes(FilterByConfig(ctx, config)).Find(&products).Statement
+		s.NoError(stmt.Error)
+		return stmt.SQL.String()
+	}
+
+	sql := query("name__in=a,b,c&price__gte=10&page=3&limit=5&order_by=name")
+	s.Equal(`SELECT * FROM "products" WHERE "name" = ANY($1) AND "price" >= $2 ORDER BY "products"."name" DESC LIMIT $3 OFFSET $4`, sql)
+	literals := regexp.MustCompile(`'|[0-9]`)
+	s.False(literals.MatchString(regexp.MustCompile(`\$[0-9]+`).ReplaceAllString(sql, "")), sql)
+
+	s.Equal(sql, query("name__in=d&price__gte=20&page=7&limit=50&order_by=name"))
+}
@@ -0,0 +1,27 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// StreamByConfig is the same as FilterByConfig for the queries iterated with
+// Rows, eg : a large export. The pagination is never applied, whatever the
+// flags of config, so no count is run and every matching row is returned.
+// Example:
+//
+//	rows, err := db.Model(&UserModel{}).Scopes(filter.StreamByConfig(c, filter.Config{Flags: filter.ALL})).Rows()
+//	defer rows.Close()
+//	for rows.Next() {
+//		var user UserModel
+//		db.ScanRows(rows, &user)
+//	}
+func StreamByConfig(c *gin.Context, config Config) func(db *gorm.DB) *gorm.DB {
+	config.Flags &^= PAGINATE
+	return FilterByConfig(c, config)
+}
@@ -0,0 +1,32 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// TestStreamRows checks that the streamed rows are filtered and ordered
+// without count nor pagination.
+func (s *TestSuite) TestStreamRows() {
+	ctx := newTestContext("username=john&page=2&limit=10")
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE "username" = \$1 ORDER BY "users"\."created_at" DESC$`).
+		WithArgs("john").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username"}).AddRow(1, "john").AddRow(2, "john"))
+	rows, err := s.db.Model(&User{}).Scopes(StreamByConfig(ctx, Config{Flags: ALL})).Rows()
+	s.Require().NoError(err)
+	defer rows.Close()
+
+	var users []User
+	for rows.Next() {
+		var user User
+		s.NoError(s.db.ScanRows(rows, &user))
+		users = append(users, user)
+	}
+	s.Len(users, 2)
+	s.NoError(s.mock.ExpectationsWereMet())
+}
@@ -0,0 +1,27 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// UnionQueries returns a query per model filtered and searched by the same
+// request, to be combined with UNION. The pagination and the order are left
+// to the combined query, they are not applied to the subqueries.
+// Example:
+//
+//	queries := filter.UnionQueries(c, db, filter.Config{Flags: filter.ALL}, &Post{}, &Video{})
+//	db.Raw("? UNION ALL ? LIMIT 20", queries[0].Select("id", "title"), queries[1].Select("id", "title")).Scan(&results)
+func UnionQueries(c *gin.Context, db *gorm.DB, config Config, models ...interface{}) []*gorm.DB {
+	config.Flags &^= PAGINATE | ORDER_BY
+	queries := make([]*gorm.DB, 0, len(models))
+	for _, model := range models {
+		queries = append(queries, db.Session(&gorm.Session{NewDB: true}).Model(model).Scopes(FilterByConfig(c, config)))
+	}
+	return queries
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type Album struct {
+	Id    int64  `filter:"filterable"`
+	Title string `filter:"filterable;searchable"`
+}
+
+type Single struct {
+	Id    int64  `filter:"filterable"`
+	Title string `filter:"filterable;searchable"`
+}
+
+// TestUnionQueries checks that both sides of the UNION are filtered and
+// searched, without their own pagination.
+func (s *TestSuite) TestUnionQueries() {
+	var titles []string
+	ctx := newTestContext("search=love&id__gt=10&page=2")
+
+	queries := UnionQueries(ctx, s.db, Config{Flags: ALL}, &Album{}, &Single{})
+	s.Len(queries, 2)
+
+	s.mock.ExpectQuery(`^SELECT "title" FROM "albums" WHERE "id" > \$1 AND "title" LIKE \$2 UNION SELECT "title" FROM "singles" WHERE "id" > \$3 AND "title" LIKE \$4$`).
+		WithArgs("10", "%love%", "10", "%love%").
+		WillReturnRows(sqlmock.NewRows([]string{"title"}))
+	err := s.db.Raw("? UNION ?", queries[0].Select("title"), queries[1].Select("title")).Scan(&titles).Error
+	s.NoError(err)
+}
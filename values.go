@@ -0,0 +1,32 @@
+// Copyright (c) 2021 MagellanCL
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"net/url"
+	"strings"
+)
+
+// normalizeValue cleans a value sent by a client, eg : `"john doe"` or
+// "john%20doe/" become "john doe". A value is decoded once more only if it is
+// still a valid escaped string, and "+" is kept as is.
+func normalizeValue(value string) string {
+	if strings.Contains(value, "%") {
+		if decoded, err := url.PathUnescape(value); err == nil {
+			value = decoded
+		}
+	}
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 {
+		if first := value[0]; (first == '"' || first == '\'') && value[len(value)-1] == first {
+			value = strings.TrimSpace(value[1 : len(value)-1])
+		}
+	}
+	if trimmed := strings.TrimRight(value, "/"); trimmed != "" {
+		value = trimmed
+	}
+	return value
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2022 ActiveChooN
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package filter
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// TestFiltersNormalizeValues checks that the quotes, the trailing slashes and
+// the double encoding are removed from the values when enabled.
+func (s *TestSuite) TestFiltersNormalizeValues() {
+	var users []User
+	config := Config{Flags: FILTER, NormalizeValues: true}
+	for query, arg := range map[string]string{
+		"username=%22john%22":    "john",
+		"username=%27john%27":    "john",
+		"username=john%2F":       "john",
+		"username=%20%22john%22": "john",
+		"username=john%2520doe":  "john doe",
+		"username=a%2Bb":         "a+b",
+	} {
+		s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE "username" = \$1$`).
+			WithArgs(arg).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "username"}))
+		err := s.db.Model(&User{}).Scopes(FilterByConfig(newTestContext(query), config)).Find(&users).Error
+		s.NoError(err, query)
+	}
+
+	s.mock.ExpectQuery(`^SELECT \* FROM "users" WHERE "username" = \$1$`).
+		WithArgs(`"john"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username"}))
+	err := s.db.Model(&User{}).Scopes(FilterByConfig(newTestContext("username=%22john%22"), Config{Flags: FILTER})).Find(&users).Error
+	s.NoError(err)
+}